@@ -138,6 +138,85 @@ func GetAsDuration(val string, defaultVal time.Duration) (
 	return valDuration, false
 }
 
+// ListSeparator separates the elements of list values, like "1,2,3"
+const ListSeparator = ","
+
+// GetAsStrings converts the input string to a slice of strings, splitting it by ListSeparator.
+// It returns the trimmed elements and false if none of them is empty after trimming.
+// If the input is empty, contains only whitespace, or has empty elements (like "a,,b"),
+// it returns the defaultVal and true.
+//
+// Parameters:
+//   - val: The input string to be converted, like "a,b,c"
+//   - defaultVal: The default value to return if the input is invalid
+//
+// Returns:
+//   - parsedVal: The parsed elements or defaultVal if input is invalid
+//   - defaultUsed: True if defaultVal was used, false otherwise
+func GetAsStrings(val string, defaultVal []string) (parsedVal []string, defaultUsed bool) {
+	return getAsSlice(val, defaultVal, GetAsString)
+}
+
+// GetAsInts converts the input string to a slice of integers, splitting it by ListSeparator.
+// It returns the parsed integers and false if every element is a valid integer.
+// If the input is empty, contains only whitespace, or has an element which is not a valid
+// integer, it returns the defaultVal and true.
+//
+// Parameters:
+//   - val: The input string to be converted, like "1,2,3"
+//   - defaultVal: The default value to return if the input is invalid
+//
+// Returns:
+//   - parsedVal: The parsed integers or defaultVal if input is invalid
+//   - defaultUsed: True if defaultVal was used, false otherwise
+func GetAsInts(val string, defaultVal []int) (parsedVal []int, defaultUsed bool) {
+	return getAsSlice(val, defaultVal, GetAsInt)
+}
+
+// GetAsDurations converts the input string to a slice of time.Duration values, splitting it by
+// ListSeparator. It returns the parsed durations and false if every element is a valid
+// duration string. If the input is empty, contains only whitespace, or has an element which is
+// not a valid duration, it returns the defaultVal and true.
+//
+// Parameters:
+//   - val: The input string to be converted, like "1s,500ms,2h"
+//   - defaultVal: The default value to return if the input is invalid
+//
+// Returns:
+//   - parsedVal: The parsed durations or defaultVal if input is invalid
+//   - defaultUsed: True if defaultVal was used, false otherwise
+func GetAsDurations(val string, defaultVal []time.Duration) (
+	parsedVal []time.Duration,
+	defaultUsed bool,
+) {
+	return getAsSlice(val, defaultVal, GetAsDuration)
+}
+
+// getAsSlice splits the input by ListSeparator and converts every element with getAs. The
+// defaultVal is returned if the input is empty or any element cannot be converted.
+func getAsSlice[T any](
+	val string,
+	defaultVal []T,
+	getAs func(val string, defaultVal T) (T, bool),
+) ([]T, bool) {
+	if strings.TrimSpace(val) == "" {
+		return defaultVal, true
+	}
+
+	elements := strings.Split(val, ListSeparator)
+	parsedVal := make([]T, 0, len(elements))
+	for _, element := range elements {
+		var zero T
+		parsedElement, defaultUsed := getAs(element, zero)
+		if defaultUsed {
+			return defaultVal, true
+		}
+		parsedVal = append(parsedVal, parsedElement)
+	}
+
+	return parsedVal, false
+}
+
 type RawVal string
 
 func (rawVal RawVal) GetAsString(defaultVal string) (string, bool) {
@@ -159,3 +238,15 @@ func (rawVal RawVal) GetAsFloat(defaultVal float64) (float64, bool) {
 func (rawVal RawVal) GetAsDuration(defaultVal time.Duration) (time.Duration, bool) {
 	return GetAsDuration(string(rawVal), defaultVal)
 }
+
+func (rawVal RawVal) GetAsStrings(defaultVal []string) ([]string, bool) {
+	return GetAsStrings(string(rawVal), defaultVal)
+}
+
+func (rawVal RawVal) GetAsInts(defaultVal []int) ([]int, bool) {
+	return GetAsInts(string(rawVal), defaultVal)
+}
+
+func (rawVal RawVal) GetAsDurations(defaultVal []time.Duration) ([]time.Duration, bool) {
+	return GetAsDurations(string(rawVal), defaultVal)
+}
@@ -335,3 +335,152 @@ func (suite *StrconvSuite) TestGetAsDuration() {
 		)
 	}
 }
+
+func (suite *StrconvSuite) TestGetAsStrings() {
+	tests := []struct {
+		name        string
+		input       string
+		defaultVal  []string
+		want        []string
+		wantDefault bool
+	}{
+		{
+			name:        "empty string returns default",
+			input:       "",
+			defaultVal:  []string{"default"},
+			want:        []string{"default"},
+			wantDefault: true,
+		},
+		{
+			name:        "single value returns one element",
+			input:       " a ",
+			defaultVal:  nil,
+			want:        []string{"a"},
+			wantDefault: false,
+		},
+		{
+			name:        "list returns trimmed elements",
+			input:       "a, b ,c",
+			defaultVal:  nil,
+			want:        []string{"a", "b", "c"},
+			wantDefault: false,
+		},
+		{
+			name:        "empty element returns default",
+			input:       "a,,c",
+			defaultVal:  []string{"default"},
+			want:        []string{"default"},
+			wantDefault: true,
+		},
+	}
+
+	for _, scenario := range tests {
+		suite.Run(
+			scenario.name, func() {
+				got, gotDefault := GetAsStrings(scenario.input, scenario.defaultVal)
+				suite.Equal(scenario.want, got, "GetAsStrings() value")
+				suite.Equal(scenario.wantDefault, gotDefault, "GetAsStrings() defaultUsed")
+
+				got, gotDefault = RawVal(scenario.input).GetAsStrings(scenario.defaultVal)
+				suite.Equal(scenario.want, got, "RawVal.GetAsStrings() value")
+				suite.Equal(scenario.wantDefault, gotDefault, "RawVal.GetAsStrings() defaultUsed")
+			},
+		)
+	}
+}
+
+func (suite *StrconvSuite) TestGetAsInts() {
+	tests := []struct {
+		name        string
+		input       string
+		defaultVal  []int
+		want        []int
+		wantDefault bool
+	}{
+		{
+			name:        "whitespace returns default",
+			input:       "   ",
+			defaultVal:  []int{0},
+			want:        []int{0},
+			wantDefault: true,
+		},
+		{
+			name:        "list returns parsed elements",
+			input:       "1, 2,-3",
+			defaultVal:  nil,
+			want:        []int{1, 2, -3},
+			wantDefault: false,
+		},
+		{
+			name:        "invalid element returns default",
+			input:       "1,abc",
+			defaultVal:  []int{0},
+			want:        []int{0},
+			wantDefault: true,
+		},
+	}
+
+	for _, scenario := range tests {
+		suite.Run(
+			scenario.name, func() {
+				got, gotDefault := GetAsInts(scenario.input, scenario.defaultVal)
+				suite.Equal(scenario.want, got, "GetAsInts() value")
+				suite.Equal(scenario.wantDefault, gotDefault, "GetAsInts() defaultUsed")
+
+				got, gotDefault = RawVal(scenario.input).GetAsInts(scenario.defaultVal)
+				suite.Equal(scenario.want, got, "RawVal.GetAsInts() value")
+				suite.Equal(scenario.wantDefault, gotDefault, "RawVal.GetAsInts() defaultUsed")
+			},
+		)
+	}
+}
+
+func (suite *StrconvSuite) TestGetAsDurations() {
+	tests := []struct {
+		name        string
+		input       string
+		defaultVal  []time.Duration
+		want        []time.Duration
+		wantDefault bool
+	}{
+		{
+			name:        "empty string returns default",
+			input:       "",
+			defaultVal:  []time.Duration{time.Second},
+			want:        []time.Duration{time.Second},
+			wantDefault: true,
+		},
+		{
+			name:        "list returns parsed elements",
+			input:       "1s,500ms, 2h",
+			defaultVal:  nil,
+			want:        []time.Duration{time.Second, 500 * time.Millisecond, 2 * time.Hour},
+			wantDefault: false,
+		},
+		{
+			name:        "invalid element returns default",
+			input:       "1s,1x",
+			defaultVal:  nil,
+			want:        nil,
+			wantDefault: true,
+		},
+	}
+
+	for _, scenario := range tests {
+		suite.Run(
+			scenario.name, func() {
+				got, gotDefault := GetAsDurations(scenario.input, scenario.defaultVal)
+				suite.Equal(scenario.want, got, "GetAsDurations() value")
+				suite.Equal(scenario.wantDefault, gotDefault, "GetAsDurations() defaultUsed")
+
+				got, gotDefault = RawVal(scenario.input).GetAsDurations(scenario.defaultVal)
+				suite.Equal(scenario.want, got, "RawVal.GetAsDurations() value")
+				suite.Equal(
+					scenario.wantDefault,
+					gotDefault,
+					"RawVal.GetAsDurations() defaultUsed",
+				)
+			},
+		)
+	}
+}
@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"github.com/rsgcata/gocommon/params"
+	"io"
+	"slices"
+	"strings"
+)
+
+type InputArgumentDefinition struct {
+	name        string
+	description string
+	required    bool
+	variadic    bool
+}
+
+func (def InputArgumentDefinition) Name() string {
+	return def.name
+}
+
+func (def InputArgumentDefinition) Description() string {
+	return def.description
+}
+
+func (def InputArgumentDefinition) Required() bool {
+	return def.required
+}
+
+// Variadic reports if the argument collects all the remaining positional values
+func (def InputArgumentDefinition) Variadic() bool {
+	return def.variadic
+}
+
+// usage builds the argument usage label, like <file>, [<file>] or [<file>...]
+func (def InputArgumentDefinition) usage() string {
+	label := "<" + def.name + ">"
+	if def.variadic {
+		label += "..."
+	}
+	if !def.required {
+		label = "[" + label + "]"
+	}
+	return label
+}
+
+// InputArgumentDefinitionList holds the positional arguments definitions, in the order in
+// which they are expected on the command line
+type InputArgumentDefinitionList []InputArgumentDefinition
+
+func (definitions InputArgumentDefinitionList) usage() string {
+	labels := make([]string, 0, len(definitions))
+	for _, def := range definitions {
+		labels = append(labels, def.usage())
+	}
+	return strings.Join(labels, " ")
+}
+
+// InputArgumentDefinitionBuilder is a fluent builder for InputArgumentDefinition. Use
+// NewArgument to create one.
+type InputArgumentDefinitionBuilder struct {
+	definition InputArgumentDefinition
+}
+
+// NewArgument starts building a positional argument definition with the given name. The
+// name is used to retrieve the value from InputArgumentsMap and in help output.
+func NewArgument(name string) *InputArgumentDefinitionBuilder {
+	return &InputArgumentDefinitionBuilder{definition: InputArgumentDefinition{name: name}}
+}
+
+func (builder *InputArgumentDefinitionBuilder) Description(
+	description string,
+) *InputArgumentDefinitionBuilder {
+	builder.definition.description = description
+	return builder
+}
+
+func (builder *InputArgumentDefinitionBuilder) Required() *InputArgumentDefinitionBuilder {
+	builder.definition.required = true
+	return builder
+}
+
+// Variadic makes the argument collect all the remaining positional values. Only the last
+// argument can be variadic.
+func (builder *InputArgumentDefinitionBuilder) Variadic() *InputArgumentDefinitionBuilder {
+	builder.definition.variadic = true
+	return builder
+}
+
+// Build validates and returns the argument definition.
+func (builder *InputArgumentDefinitionBuilder) Build() (InputArgumentDefinition, error) {
+	if !optionNamePattern.MatchString(builder.definition.name) {
+		return InputArgumentDefinition{}, fmt.Errorf(
+			"argument name '%s' is invalid, only letters, digits, '-' and '_' are allowed"+
+				" and it must start with a letter or digit",
+			builder.definition.name,
+		)
+	}
+	return builder.definition, nil
+}
+
+// NewInputArgumentDefinitionList builds all the provided argument definitions, keeping their
+// order. Names must be unique, required arguments cannot follow optional ones and only the
+// last argument can be variadic. All validation errors are returned joined together.
+func NewInputArgumentDefinitionList(
+	builders ...*InputArgumentDefinitionBuilder,
+) (InputArgumentDefinitionList, error) {
+	definitions := make(InputArgumentDefinitionList, 0, len(builders))
+	usedNames := map[string]bool{}
+	var errs []error
+	for i, builder := range builders {
+		definition, err := builder.Build()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if usedNames[definition.name] {
+			errs = append(errs, fmt.Errorf("argument '%s' is defined twice", definition.name))
+		}
+		usedNames[definition.name] = true
+
+		if definition.variadic && i != len(builders)-1 {
+			errs = append(
+				errs,
+				fmt.Errorf(
+					"argument '%s' is variadic but it is not the last one",
+					definition.name,
+				),
+			)
+		}
+
+		previousOptional := len(definitions) > 0 && !definitions[len(definitions)-1].required
+		if definition.required && previousOptional {
+			errs = append(
+				errs,
+				fmt.Errorf(
+					"required argument '%s' cannot follow an optional argument",
+					definition.name,
+				),
+			)
+		}
+		definitions = append(definitions, definition)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return definitions, nil
+}
+
+// MustInputArgumentDefinitionList is like NewInputArgumentDefinitionList but panics on error.
+func MustInputArgumentDefinitionList(
+	builders ...*InputArgumentDefinitionBuilder,
+) InputArgumentDefinitionList {
+	definitions, err := NewInputArgumentDefinitionList(builders...)
+	if err != nil {
+		panic(err)
+	}
+	return definitions
+}
+
+type InputArgument struct {
+	InputArgumentDefinition
+	rawVals []string
+}
+
+// RawVal returns the first value of the argument, which is the only one for arguments that
+// are not variadic
+func (arg InputArgument) RawVal() params.RawVal {
+	if len(arg.rawVals) == 0 {
+		return ""
+	}
+	return params.RawVal(arg.rawVals[0])
+}
+
+// RawVals returns all the values of the argument
+func (arg InputArgument) RawVals() []params.RawVal {
+	vals := make([]params.RawVal, 0, len(arg.rawVals))
+	for _, val := range arg.rawVals {
+		vals = append(vals, params.RawVal(val))
+	}
+	return vals
+}
+
+type InputArgumentsMap map[string]InputArgument
+
+// ArgumentsCommand is implemented by commands which accept positional arguments. For these
+// commands, ExecWithArguments is called instead of Exec.
+type ArgumentsCommand interface {
+	Command
+	ArgumentsDefinition() InputArgumentDefinitionList
+	ExecWithArguments(
+		arguments InputArgumentsMap,
+		options InputOptionsMap,
+		stdWriter io.Writer,
+	) error
+}
+
+// argumentsDefiner is implemented by ArgumentsCommand, and optionally by ContextCommand, to
+// declare positional arguments
+type argumentsDefiner interface {
+	ArgumentsDefinition() InputArgumentDefinitionList
+}
+
+// argumentsDefinitionOf returns the positional arguments accepted by the command, if any
+func argumentsDefinitionOf(cmd Command) InputArgumentDefinitionList {
+	if definer, ok := cmd.(argumentsDefiner); ok {
+		return definer.ArgumentsDefinition()
+	}
+	return nil
+}
+
+// BuildArgumentsFrom assigns the positional values to the argument definitions, in order,
+// and checks their arity. Arguments without values are not present in the returned map.
+func BuildArgumentsFrom(
+	positional []string,
+	definitions InputArgumentDefinitionList,
+) (InputArgumentsMap, []error) {
+	arguments := InputArgumentsMap{}
+	var argumentErrors []error
+	remaining := positional
+	for _, def := range definitions {
+		if len(remaining) == 0 {
+			if def.required {
+				argumentErrors = append(
+					argumentErrors,
+					fmt.Errorf("argument '%s' is required", def.name),
+				)
+			}
+			continue
+		}
+
+		take := 1
+		if def.variadic {
+			take = len(remaining)
+		}
+		arguments[def.name] = InputArgument{
+			InputArgumentDefinition: def,
+			rawVals:                 slices.Clone(remaining[:take]),
+		}
+		remaining = remaining[take:]
+	}
+
+	if len(remaining) > 0 {
+		argumentErrors = append(
+			argumentErrors,
+			fmt.Errorf(
+				"too many arguments, expected at most %d, got %d",
+				len(definitions),
+				len(positional),
+			),
+		)
+	}
+
+	return arguments, argumentErrors
+}
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"github.com/rsgcata/gocommon/params"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"testing"
+)
+
+type ArgumentsSuite struct {
+	suite.Suite
+}
+
+func TestArgumentsSuite(t *testing.T) {
+	suite.Run(t, new(ArgumentsSuite))
+}
+
+// Mock command accepting positional arguments
+type argumentsMockCommand struct {
+	bootstrapMockCommand
+	argumentsDef InputArgumentDefinitionList
+	execArgsFunc func(arguments InputArgumentsMap, options InputOptionsMap, writer io.Writer) error
+}
+
+func (m *argumentsMockCommand) ArgumentsDefinition() InputArgumentDefinitionList {
+	return m.argumentsDef
+}
+
+func (m *argumentsMockCommand) ExecWithArguments(
+	arguments InputArgumentsMap,
+	options InputOptionsMap,
+	writer io.Writer,
+) error {
+	if m.execArgsFunc != nil {
+		return m.execArgsFunc(arguments, options, writer)
+	}
+	return nil
+}
+
+func (s *ArgumentsSuite) TestItCanBuildArgumentDefinitionLists() {
+	definitions, err := NewInputArgumentDefinitionList(
+		NewArgument("source").Description("Source file").Required(),
+		NewArgument("target"),
+		NewArgument("extra").Variadic(),
+	)
+
+	s.NoError(err)
+	s.Len(definitions, 3)
+	s.Equal("source", definitions[0].Name())
+	s.Equal("Source file", definitions[0].Description())
+	s.True(definitions[0].Required())
+	s.False(definitions[1].Required())
+	s.True(definitions[2].Variadic())
+	s.Equal("<source> [<target>] [<extra>...]", definitions.usage())
+}
+
+func (s *ArgumentsSuite) TestItFailsToBuildInvalidArgumentDefinitionLists() {
+	tests := []struct {
+		name     string
+		builders []*InputArgumentDefinitionBuilder
+	}{
+		{"Invalid name", []*InputArgumentDefinitionBuilder{NewArgument("in valid")}},
+		{
+			"Duplicate name",
+			[]*InputArgumentDefinitionBuilder{NewArgument("file"), NewArgument("file")},
+		},
+		{
+			"Variadic not last",
+			[]*InputArgumentDefinitionBuilder{NewArgument("files").Variadic(), NewArgument("x")},
+		},
+		{
+			"Required after optional",
+			[]*InputArgumentDefinitionBuilder{NewArgument("a"), NewArgument("b").Required()},
+		},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				_, err := NewInputArgumentDefinitionList(scenario.builders...)
+				s.Error(err)
+				s.Panics(
+					func() {
+						MustInputArgumentDefinitionList(scenario.builders...)
+					},
+				)
+			},
+		)
+	}
+}
+
+func (s *ArgumentsSuite) TestItCanBuildArgumentsFromPositionalValues() {
+	definitions := MustInputArgumentDefinitionList(
+		NewArgument("source").Required(),
+		NewArgument("files").Variadic(),
+	)
+
+	tests := []struct {
+		name       string
+		positional []string
+		want       map[string][]params.RawVal
+		wantErrors bool
+	}{
+		{
+			name:       "Missing required argument",
+			positional: nil,
+			want:       map[string][]params.RawVal{},
+			wantErrors: true,
+		},
+		{
+			name:       "Only required argument",
+			positional: []string{"a.csv"},
+			want:       map[string][]params.RawVal{"source": {"a.csv"}},
+		},
+		{
+			name:       "Variadic argument collects the rest",
+			positional: []string{"a.csv", "b.csv", "c.csv"},
+			want: map[string][]params.RawVal{
+				"source": {"a.csv"},
+				"files":  {"b.csv", "c.csv"},
+			},
+		},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				arguments, errs := BuildArgumentsFrom(scenario.positional, definitions)
+				if scenario.wantErrors {
+					s.NotEmpty(errs)
+				} else {
+					s.Empty(errs)
+				}
+
+				s.Len(arguments, len(scenario.want))
+				for name, values := range scenario.want {
+					s.Equal(values, arguments[name].RawVals())
+					s.Equal(values[0], arguments[name].RawVal())
+				}
+			},
+		)
+	}
+
+	_, errs := BuildArgumentsFrom(
+		[]string{"a", "b", "c"},
+		MustInputArgumentDefinitionList(NewArgument("a"), NewArgument("b")),
+	)
+	s.Len(errs, 1)
+	s.Contains(errs[0].Error(), "too many arguments")
+	s.Equal(params.RawVal(""), InputArgument{}.RawVal())
+}
+
+func (s *ArgumentsSuite) TestItPassesArgumentsToCommandsAcceptingThem() {
+	var gotArguments InputArgumentsMap
+	var gotOptions InputOptionsMap
+	cmd := &argumentsMockCommand{
+		bootstrapMockCommand: bootstrapMockCommand{
+			id:       "import",
+			inputDef: MustInputOptionDefinitionMap(NewOption("dry-run").Flag()),
+		},
+		argumentsDef: MustInputArgumentDefinitionList(NewArgument("files").Required().Variadic()),
+		execArgsFunc: func(
+			arguments InputArgumentsMap,
+			options InputOptionsMap,
+			_ io.Writer,
+		) error {
+			gotArguments = arguments
+			gotOptions = options
+			return nil
+		},
+	}
+
+	err := runCommand(
+		context.Background(),
+		cmd,
+		[]string{"users.csv", "--dry-run", "orders.csv"},
+		IO{Stdout: &bytes.Buffer{}},
+		runSettings{},
+	)
+
+	s.NoError(err)
+	s.Equal([]params.RawVal{"users.csv", "orders.csv"}, gotArguments["files"].RawVals())
+	s.Equal(params.RawVal("true"), gotOptions["dry-run"].RawVal())
+
+	err = runCommand(
+		context.Background(),
+		cmd,
+		[]string{"--dry-run"},
+		IO{Stdout: &bytes.Buffer{}},
+		runSettings{},
+	)
+	s.Error(err)
+	s.Contains(err.Error(), "argument 'files' is required")
+}
+
+func (s *ArgumentsSuite) TestHelpShowsArgumentsUsage() {
+	cmd := &HelpCommand{
+		availableCommands: []Command{
+			&argumentsMockCommand{
+				bootstrapMockCommand: bootstrapMockCommand{
+					id:       "import",
+					inputDef: InputOptionDefinitionMap{},
+				},
+				argumentsDef: MustInputArgumentDefinitionList(
+					NewArgument("source").Description("Source file").Required(),
+					NewArgument("files").Variadic(),
+				),
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	s.NoError(cmd.Exec(InputOptionsMap{}, &buf))
+	s.Contains(buf.String(), "Usage: import <source> [<files>...]")
+	s.Contains(buf.String(), "Arguments:")
+	s.Contains(buf.String(), "<source> Source file")
+}
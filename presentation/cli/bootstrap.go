@@ -1,25 +1,42 @@
 package cli
 
 import (
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/rsgcata/gocommon/params"
 	"io"
+	"log/slog"
 	"maps"
 	"os"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 )
 
 const StatusOk = 0
 const StatusErr = 1
 
+// StatusInterrupted is used when the command was stopped by SIGINT or SIGTERM, following the
+// shell convention of 128 + SIGINT
+const StatusInterrupted = 130
+
 type InputOptionDefinition struct {
 	name        string
 	description string
 	required    bool
 	defaultVal  string
+	valueType   OptionType
+	enumValues  []string
+	aliases     []string
+	envVar      string
+	repeatable  bool
+	list        bool
+	sensitive   bool
 }
 
 func (def InputOptionDefinition) Name() string {
@@ -38,15 +55,140 @@ func (def InputOptionDefinition) DefaultValue() string {
 	return def.defaultVal
 }
 
+func (def InputOptionDefinition) Type() OptionType {
+	return def.valueType
+}
+
+// EnumValues returns the allowed values for options of type OptionTypeEnum
+func (def InputOptionDefinition) EnumValues() []string {
+	return slices.Clone(def.enumValues)
+}
+
+// Aliases returns the alternative names of the option. Single character aliases are used
+// with a single dash (-v), the others with two dashes (--verb).
+func (def InputOptionDefinition) Aliases() []string {
+	return slices.Clone(def.aliases)
+}
+
+// EnvVar returns the name of the environment variable used when the option is not provided
+// on the command line, empty if the option is not bound to one
+func (def InputOptionDefinition) EnvVar() string {
+	return def.envVar
+}
+
+// IsRepeatable reports if the option can be provided more than once (--tag=a --tag=b). List
+// options are always repeatable.
+func (def InputOptionDefinition) IsRepeatable() bool {
+	return def.repeatable || def.list
+}
+
+// IsList reports if the option values are lists separated by params.ListSeparator
+// (--ids=1,2,3)
+func (def InputOptionDefinition) IsList() bool {
+	return def.list
+}
+
+// splitValue returns the values held by a raw option value, more than one only for list
+// options. Empty list elements are dropped.
+func (def InputOptionDefinition) splitValue(rawVal string) []string {
+	if !def.list {
+		return []string{rawVal}
+	}
+
+	values := []string{}
+	for _, value := range strings.Split(rawVal, params.ListSeparator) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// IsSensitive reports if the option holds a secret, like a password. Sensitive values are
+// read without echo when prompted for.
+func (def InputOptionDefinition) IsSensitive() bool {
+	return def.sensitive
+}
+
+// IsFlag reports if the option is a boolean flag. Flags do not take a separate value:
+// --name sets them to true and --no-name sets them to false, except for flags already named
+// "no-...". Single character aliases of flags can be grouped (-abc).
+func (def InputOptionDefinition) IsFlag() bool {
+	return def.valueType == OptionTypeBool
+}
+
+// isNegatable reports if the flag can be set to false with --no-name. Flags already named
+// "no-..." are not negated again, --no-no-color would be confusing.
+func (def InputOptionDefinition) isNegatable() bool {
+	return def.IsFlag() && !strings.HasPrefix(def.name, "no-")
+}
+
+// ValueSource tells where the value of an InputOption comes from
+type ValueSource int
+
+const (
+	// ValueSourceOther is used for options which were not built from the command line input,
+	// for example the ones created manually in tests
+	ValueSourceOther ValueSource = iota
+	// ValueSourceUser is used for options explicitly provided by the user
+	ValueSourceUser
+	// ValueSourceDefault is used for options omitted by the user and filled with the default
+	// value declared in their definition
+	ValueSourceDefault
+	// ValueSourceEnv is used for options omitted by the user and filled from the environment
+	// variable bound to them
+	ValueSourceEnv
+	// ValueSourcePrompt is used for required options omitted by the user and asked for
+	// interactively
+	ValueSourcePrompt
+)
+
 type InputOption struct {
 	InputOptionDefinition
-	rawVal string
+	rawVal  string
+	rawVals []string
+	source  ValueSource
 }
 
+// newInputOption creates an option holding the provided values. The raw value of options
+// with more than one value is the values joined by params.ListSeparator.
+func newInputOption(def InputOptionDefinition, values []string, source ValueSource) InputOption {
+	return InputOption{
+		InputOptionDefinition: def,
+		rawVal:                strings.Join(values, params.ListSeparator),
+		rawVals:               values,
+		source:                source,
+	}
+}
+
+// RawVal returns the option value. For list options, all the values are joined by
+// params.ListSeparator, so they can be read with the params.RawVal slice accessors, like
+// RawVal().GetAsInts(nil). Repeatable options are joined the same way, but their values may
+// contain the separator themselves, so they should be read with RawVals instead.
 func (opt InputOption) RawVal() params.RawVal {
 	return params.RawVal(opt.rawVal)
 }
 
+// RawVals returns every value of the option, in the order they were provided
+func (opt InputOption) RawVals() []params.RawVal {
+	if opt.rawVals == nil {
+		if opt.rawVal == "" {
+			return nil
+		}
+		return []params.RawVal{params.RawVal(opt.rawVal)}
+	}
+
+	rawVals := make([]params.RawVal, 0, len(opt.rawVals))
+	for _, rawVal := range opt.rawVals {
+		rawVals = append(rawVals, params.RawVal(rawVal))
+	}
+	return rawVals
+}
+
+func (opt InputOption) Source() ValueSource {
+	return opt.source
+}
+
 type InputOptionDefinitionMap map[string]InputOptionDefinition
 type InputOptionsMap map[string]InputOption
 
@@ -57,80 +199,275 @@ type Command interface {
 	InputDefinition() InputOptionDefinitionMap
 }
 
+// LenientCommand is implemented by commands which accept options that are not declared in
+// their InputDefinition. By default, undeclared options are rejected as usage errors.
+type LenientCommand interface {
+	Command
+	AcceptsUnknownOptions() bool
+}
+
+func acceptsUnknownOptions(cmd Command) bool {
+	lenientCmd, ok := cmd.(LenientCommand)
+	return ok && lenientCmd.AcceptsUnknownOptions()
+}
+
+// unknownOptionError builds the error for an undeclared option, suggesting the closest
+// declared option names
+func unknownOptionError(name string, definitions InputOptionDefinitionMap) error {
+	var candidates []string
+	for _, def := range definitions {
+		candidates = append(candidates, def.name)
+		candidates = append(candidates, def.aliases...)
+	}
+
+	suggestions := suggest(name, candidates)
+	if len(suggestions) == 0 {
+		return fmt.Errorf("option '%s' is not defined", name)
+	}
+
+	for i, suggestion := range suggestions {
+		suggestions[i] = optionFlagName(suggestion)
+	}
+	return fmt.Errorf(
+		"option '%s' is not defined, did you mean %s?",
+		name,
+		strings.Join(suggestions, ", "),
+	)
+}
+
+// BuildOptionsFrom parses the raw command line arguments against the command input
+// definition. Undeclared options are reported as errors, unless the command implements
+// LenientCommand. Options missing from the command line are filled from their environment
+// variable (see InputOptionDefinitionBuilder.Env) or else from their default value.
 func BuildOptionsFrom(
 	rawOptions []string,
 	cmd Command,
+) (InputOptionsMap, []error) {
+	definitions := cmd.InputDefinition()
+	parsed := parseArgs(rawOptions, definitions)
+	options, errs := buildOptions(parsed.options, definitions, acceptsUnknownOptions(cmd))
+	return options, append(parsed.errs, errs...)
+}
+
+func buildOptions(
+	rawOptions []rawOption,
+	definitions InputOptionDefinitionMap,
+	allowUnknown bool,
 ) (InputOptionsMap, []error) {
 	options := InputOptionsMap{}
 	var optionErrors []error
-	for _, arg := range rawOptions {
-		if !strings.HasPrefix(arg, "--") {
+	for _, rawOpt := range rawOptions {
+		if _, declared := definitions[rawOpt.name]; !declared && !allowUnknown {
+			optionErrors = append(optionErrors, unknownOptionError(rawOpt.name, definitions))
 			continue
 		}
-		parts := strings.SplitN(strings.TrimLeft(arg, "--"), "=", 2)
-		if len(parts) < 1 {
-			continue
-		}
-
-		optionName := strings.TrimSpace(parts[0])
-		if _, exists := options[optionName]; exists {
-			optionErrors = append(
-				optionErrors,
-				fmt.Errorf("option '%s' is defined twice", optionName),
-			)
-		}
 
-		optionValue := ""
-		if len(parts) == 2 {
-			optionValue = strings.TrimSpace(parts[1])
+		def := definitions[rawOpt.name]
+		values := def.splitValue(rawOpt.value)
+		if option, exists := options[rawOpt.name]; exists {
+			if def.IsRepeatable() {
+				values = append(option.rawVals, values...)
+			} else {
+				optionErrors = append(
+					optionErrors,
+					fmt.Errorf("option '%s' is defined twice", rawOpt.name),
+				)
+			}
 		}
 
-		options[optionName] = InputOption{
-			InputOptionDefinition: cmd.InputDefinition()[optionName],
-			rawVal:                optionValue,
-		}
+		options[rawOpt.name] = newInputOption(def, values, ValueSourceUser)
 	}
 
-	for _, optionDef := range cmd.InputDefinition() {
+	for _, optionDef := range definitions {
 		option, optionSet := options[optionDef.name]
+		if !optionSet && optionDef.envVar != "" {
+			if envVal, defaultUsed := params.GetEnvAsString(optionDef.envVar, ""); !defaultUsed {
+				values := optionDef.splitValue(envVal)
+				if err := optionDef.validateValues(values); err != nil {
+					optionErrors = append(
+						optionErrors,
+						fmt.Errorf("environment variable %s: %w", optionDef.envVar, err),
+					)
+					continue
+				}
+				options[optionDef.name] = newInputOption(optionDef, values, ValueSourceEnv)
+				continue
+			}
+		}
+
 		if optionDef.required && (!optionSet || option.rawVal == "") {
 			optionErrors = append(
 				optionErrors,
 				fmt.Errorf("option '%s' is required", optionDef.name),
 			)
+			continue
+		}
+
+		if optionSet {
+			if optionDef.lacksValue(option.rawVals) {
+				optionErrors = append(
+					optionErrors,
+					fmt.Errorf("option '%s' requires a value", optionDef.name),
+				)
+			} else if err := optionDef.validateValues(option.rawVals); err != nil {
+				optionErrors = append(optionErrors, err)
+			}
+		} else if optionDef.defaultVal != "" {
+			options[optionDef.name] = newInputOption(
+				optionDef,
+				optionDef.splitValue(optionDef.defaultVal),
+				ValueSourceDefault,
+			)
 		}
 	}
 
 	return options, optionErrors
 }
 
-func runCommand(cmd Command, rawOptions []string, outputWriter io.Writer) (cmdErr error) {
+// runSettings holds the Bootstrap settings applied when running a command. The zero value
+// runs the command without prompts and without middleware.
+type runSettings struct {
+	prompter    *Prompter
+	middlewares []CommandMiddleware
+	// globalOptions are the validated global options, added to the options of the command
+	globalOptions InputOptionsMap
+	// output is the format requested with the --output global option
+	output OutputFormat
+	// stdoutStyler and stderrStyler style the output streams of the command
+	stdoutStyler *Styler
+	stderrStyler *Styler
+}
+
+// runCommand builds the command input and executes the command through the middleware
+// chain. When the prompter is interactive, missing required options are asked for before
+// building the input. A panic of the command is recovered and returned as a PanicError.
+func runCommand(
+	ctx context.Context,
+	cmd Command,
+	rawOptions []string,
+	stdio IO,
+	settings runSettings,
+) (cmdErr error) {
 	defer func() {
-		if err := recover(); err != nil {
-			cmdErr = err.(error)
+		if value := recover(); value != nil {
+			cmdErr = newPanicError(value)
 		}
 	}()
 
-	optionsMap, errs := BuildOptionsFrom(rawOptions, cmd)
-	if len(errs) > 0 {
-		return fmt.Errorf(
-			"Failed to execute command %s with error: %s\n",
-			cmd.Id(),
-			errors.Join(errs...).Error(),
-		)
+	prompter := settings.prompter
+	if prompter == nil {
+		prompter = NewPrompter(stdio.Stdin, stdio.Stderr, false, false)
+	}
+
+	parsed := parseArgs(rawOptions, cmd.InputDefinition())
+	var prompted []rawOption
+	if prompter.Interactive() {
+		var err error
+		prompted, err = promptMissingOptions(prompter, parsed.options, cmd.InputDefinition())
+		if err != nil {
+			return &commandError{cmd.Id(), &UsageError{err}}
+		}
 	}
 
-	if cmdErr = cmd.Exec(optionsMap, outputWriter); cmdErr != nil {
-		return fmt.Errorf(
-			"Failed to execute command %s with error: %s\n",
-			cmd.Id(),
-			cmdErr.Error(),
+	optionsMap, errs := buildOptions(
+		append(parsed.options, prompted...),
+		cmd.InputDefinition(),
+		acceptsUnknownOptions(cmd),
+	)
+	errs = append(parsed.errs, errs...)
+	optionsMap = withGlobalOptions(optionsMap, settings.globalOptions, cmd.InputDefinition())
+	for _, promptedOption := range prompted {
+		option := optionsMap[promptedOption.name]
+		option.source = ValueSourcePrompt
+		optionsMap[promptedOption.name] = option
+	}
+
+	var argumentsMap InputArgumentsMap
+	if definer, acceptsArguments := cmd.(argumentsDefiner); acceptsArguments {
+		var argErrs []error
+		argumentsMap, argErrs = BuildArgumentsFrom(
+			parsed.positional,
+			definer.ArgumentsDefinition(),
 		)
+		errs = append(errs, argErrs...)
+	} else if !acceptsUnknownOptions(cmd) {
+		// Commands without arguments reject stray positional values, like undeclared options
+		_, argErrs := BuildArgumentsFrom(parsed.positional, nil)
+		errs = append(errs, argErrs...)
+	}
+
+	if len(errs) > 0 {
+		return &commandError{cmd.Id(), &UsageError{errors.Join(errs...)}}
+	}
+
+	cmdErr = chainMiddlewares(execCommand, settings.middlewares)(
+		ctx,
+		Invocation{
+			IO:           stdio,
+			Command:      cmd,
+			Options:      optionsMap,
+			Arguments:    argumentsMap,
+			Prompter:     prompter,
+			Output:       cmp.Or(settings.output, OutputTable),
+			StdoutStyler: settings.stdoutStyler,
+			StderrStyler: settings.stderrStyler,
+		},
+	)
+
+	if cmdErr != nil {
+		return &commandError{cmd.Id(), cmdErr}
 	}
 
 	return cmdErr
 }
 
+// inputDefinitionOf returns the input definition of the command. A panic while building it,
+// like a duplicate option passed to MustInputOptionDefinitionMap, is returned as a PanicError.
+func inputDefinitionOf(cmd Command) (definitions InputOptionDefinitionMap, err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = newPanicError(value)
+		}
+	}()
+	return cmd.InputDefinition(), nil
+}
+
+// commandError is the error of a command run, prefixed with the command id. Bootstrap writes
+// and logs the wrapped error alone (see bareError), as it already names the command.
+type commandError struct {
+	cmdId string
+	err   error
+}
+
+func (e *commandError) Error() string {
+	return fmt.Sprintf("Failed to execute command %s with error: %s\n", e.cmdId, e.err)
+}
+
+func (e *commandError) Unwrap() error {
+	return e.err
+}
+
+// bareErrorMessage returns the error message without the command prefix added by runCommand
+// and without trailing new lines
+func bareErrorMessage(err error) string {
+	var wrapper *commandError
+	if errors.As(err, &wrapper) {
+		err = wrapper.err
+	}
+	return strings.TrimRight(err.Error(), "\n")
+}
+
+// unknownCommandError describes the unknown command, along with the most similar commands,
+// aliases or groups from the registry
+func unknownCommandError(cmdId string, registry CommandsRegistry) error {
+	message := fmt.Sprintf("The command %s does not exist\n", cmdId)
+	if suggestions := registry.suggestCommands(cmdId); len(suggestions) > 0 {
+		message += "Did you mean one of these?\n\t" + strings.Join(suggestions, "\n\t") + "\n"
+	}
+	message += "Run 'help' to see all available commands\n"
+	return errors.New(message)
+}
+
 func parseCmdInput(args []string) (cmdName string, rawOptions []string) {
 	if len(args) > 1 {
 		if args[0] == "--" {
@@ -146,83 +483,274 @@ func parseCmdInput(args []string) (cmdName string, rawOptions []string) {
 	return
 }
 
-type CommandsRegistry struct {
-	commands map[string]Command
-}
-
-func (registry *CommandsRegistry) Register(cmd Command) error {
-	if _, exists := registry.commands[cmd.Id()]; exists {
-		return fmt.Errorf("command '%s' is already registered", cmd.Id())
-	}
-	registry.commands[cmd.Id()] = cmd
-	return nil
-}
-
-func (registry *CommandsRegistry) Commands() map[string]Command {
-	cmdCopy := make(map[string]Command, len(registry.commands))
-	for name, cmd := range registry.commands {
-		cmdCopy[name] = cmd
-	}
-	return cmdCopy
-}
-
-func (registry *CommandsRegistry) Command(id string) (Command, bool) {
-	cmd, ok := registry.commands[id]
-	return cmd, ok
+// BootstrapOptions configures BootstrapWithOptions. The zero value is valid and uses the
+// defaults documented on each field.
+type BootstrapOptions struct {
+	// IO holds the streams passed to the command. Errors and diagnostics are written to
+	// IO.Stderr. Missing streams default to the process standard streams.
+	IO IO
+	// ProcessExit is called with the exit code when the command finished. Defaults to os.Exit.
+	ProcessExit func(code int)
+	// GraceTimeout is how long to wait for a ContextCommand (or ResultCommand) to return after
+	// the first SIGINT or SIGTERM, before forcing the exit with StatusInterrupted. A second
+	// signal forces the exit immediately. Other commands do not receive the context, so the
+	// first signal forces their exit. Defaults to DefaultGraceTimeout, a negative value waits
+	// indefinitely.
+	GraceTimeout time.Duration
+	// CommandPrefixMatching allows running commands by typing only a unique prefix of each
+	// command path word, like "mig" for "migrate". Disabled by default.
+	CommandPrefixMatching bool
+	// ProgramName is the name of the executable, used by the generated shell completion
+	// scripts. Defaults to the base name of os.Args[0].
+	ProgramName string
+	// IsInteractive reports if the user can be prompted for input, like the missing required
+	// options. Defaults to checking if IO.Stdin is a terminal. Prompts are always disabled by
+	// the --no-interaction flag.
+	IsInteractive func(stdin io.Reader) bool
+	// IsColorTerminal reports if colors can be written to the writer. Defaults to checking if
+	// the writer is a terminal. Colors are always disabled by the NO_COLOR environment
+	// variable and by the --no-color flag.
+	IsColorTerminal func(writer io.Writer) bool
+	// Middlewares wrap the execution of every command. They run before the middlewares
+	// registered on the CommandsRegistry, in the order they are listed.
+	Middlewares []CommandMiddleware
+	// Logger, when set, logs the start and the finish of the command, with the command id,
+	// the options (sensitive values are redacted), the duration, the exit status and the error
+	Logger *slog.Logger
+	// PanicVerbosity controls how a panic of the command is written to stderr. By default, only
+	// the panic value is written. The process exits with StatusPanic.
+	PanicVerbosity PanicVerbosity
+	// GlobalOptions are accepted by every command, before or after the command id, like
+	// "--verbose" or "--config". They are validated once and added to the options passed to
+	// the command, unless the command declares an option with the same name (or alias), in
+	// which case the option is left to the command. They are listed in the help header. The
+	// built-in --no-interaction, --yes and --output global options can be replaced by
+	// declaring options with the same name.
+	GlobalOptions InputOptionDefinitionMap
 }
 
 // Bootstrap Will bootstrap everything needed for the user CLI request. Will process the
-// user input and run the requested command. By default, will output to os.Stdout if
-// nil is provided for the io.Writer argument.
+// user input and run the requested command. For hierarchical commands, the longest command
+// path found in the input is used ("db migrate up"). If the input matches only a group of
+// commands, the help for that group is shown. The --help (or -h) flag shows the detailed help
+// of any command, unless the command declares an option with the same name, and the list of
+// commands when it is typed before any command. When stdin is a terminal, missing required
+// options are asked for, unless the --no-interaction global flag is used. The --yes global
+// flag accepts the confirmations asked by the command. The --output global option selects the
+// format of the results returned by ResultCommands. Help headings and errors are colored when
+// written to a terminal, unless the NO_COLOR environment variable is set or the --no-color
+// global flag is used. By default, will output to os.Stdout if nil is provided for the
+// io.Writer argument. Errors are written to os.Stderr, use BootstrapWithOptions to change it.
 func Bootstrap(
 	args []string,
 	availableCommands CommandsRegistry,
 	outputWriter io.Writer,
 	processExit func(code int),
 ) {
-	if outputWriter == nil {
-		outputWriter = os.Stdout
+	BootstrapWithOptions(
+		args,
+		availableCommands,
+		BootstrapOptions{IO: IO{Stdout: outputWriter}, ProcessExit: processExit},
+	)
+}
+
+// BootstrapWithOptions is like Bootstrap, with more control over the execution. The command
+// runs with a context cancelled on SIGINT or SIGTERM (see ContextCommand). The exit code is
+// StatusOk on success, StatusUsage for invalid input, the code of the first ExitCoder found
+// in the error chain or StatusErr for other errors. If the command fails after being
+// interrupted, without an ExitCoder, the process exits with StatusInterrupted.
+func BootstrapWithOptions(
+	args []string,
+	availableCommands CommandsRegistry,
+	options BootstrapOptions,
+) {
+	stdio := options.IO.withDefaults()
+
+	if options.ProcessExit == nil {
+		options.ProcessExit = os.Exit
+	}
+	var exitOnce sync.Once
+	processExit := func(code int) {
+		exitOnce.Do(
+			func() {
+				options.ProcessExit(code)
+			},
+		)
 	}
 
-	if processExit == nil {
-		processExit = os.Exit
+	if options.GraceTimeout == 0 {
+		options.GraceTimeout = DefaultGraceTimeout
 	}
 
-	_ = availableCommands.Register(
-		&HelpCommand{slices.Collect(maps.Values(availableCommands.Commands()))},
-	)
-	cmdId, rawOptions := parseCmdInput(args)
-	if cmdId == "" {
-		cmdId = (&HelpCommand{}).Id()
+	if options.ProgramName == "" {
+		options.ProgramName = filepath.Base(os.Args[0])
+	}
+
+	if options.IsInteractive == nil {
+		options.IsInteractive = isTerminal
 	}
 
+	if options.IsColorTerminal == nil {
+		options.IsColorTerminal = isColorTerminal
+	}
+
+	// The built-in commands are registered on a copy, to leave the caller registry untouched
+	availableCommands = CommandsRegistry{
+		commands:    maps.Clone(availableCommands.commands),
+		groups:      maps.Clone(availableCommands.groups),
+		aliases:     maps.Clone(availableCommands.aliases),
+		middlewares: slices.Clone(availableCommands.middlewares),
+	}
+	globals := globalOptionDefinitions(options.GlobalOptions)
+	_ = availableCommands.Register(&CompletionCommand{programName: options.ProgramName})
+	_ = availableCommands.Register(&completeCommand{registry: &availableCommands, globals: globals})
+	helpCmd := &HelpCommand{
+		availableCommands: slices.Collect(maps.Values(availableCommands.Commands())),
+		groups:            availableCommands.Groups(),
+		aliases:           availableCommands.Aliases(),
+		globalOptions:     globals,
+	}
+	_ = availableCommands.Register(helpCmd)
+	args, rawGlobals := takeGlobalOptions(args, globals, nil, true)
+	cmdName, rawOptions := parseCmdInput(args)
+	// "app --help" and "app -h" show the help overview, "app -h deploy" the help of deploy
+	helpFlag := len(args) > 0 && (args[0] == "--help" || args[0] == "-h")
+	if cmdName == "" || helpFlag {
+		cmdName = helpCmd.Id()
+	}
+
+	words := append([]string{cmdName}, rawOptions...)
+	if options.CommandPrefixMatching {
+		words = availableCommands.expandPrefixes(words)
+	}
+	cmdId, consumed, isCommand, isGroup := availableCommands.resolve(words)
+	if consumed > 0 {
+		rawOptions = words[consumed:]
+	}
+	if isGroup && len(rawOptions) > 0 && !strings.HasPrefix(rawOptions[0], "-") {
+		cmdId, isGroup = cmdId+" "+rawOptions[0], false
+	}
+
+	startTime := time.Now()
+	cmd, _ := availableCommands.Command(cmdId)
+	ctx, stopSignals := signalContext(
+		options.GraceTimeout,
+		isCommand && handlesCancellation(cmd),
+		func() {
+			_, _ = fmt.Fprintf(stdio.Stderr, "Forced exit of command %s\n", cmdId)
+			if options.Logger != nil {
+				logCommandFinish(
+					options.Logger,
+					cmdId,
+					time.Since(startTime),
+					StatusInterrupted,
+					errors.New("forced exit"),
+				)
+			}
+			processExit(StatusInterrupted)
+		},
+	)
+	// The signal handlers are released even if Bootstrap itself panics
+	defer stopSignals()
+
 	var cmdErr error
-	cmd, exists := availableCommands.Command(cmdId)
-	if !exists {
-		cmdErr = fmt.Errorf("The command %s does not exist\n", cmdId)
-	} else {
-		cmdErr = runCommand(cmd, rawOptions, outputWriter)
+	var definitionErr error
+	var commandDefinitions InputOptionDefinitionMap
+	switch {
+	case isCommand:
+		commandDefinitions, definitionErr = inputDefinitionOf(cmd)
+	case isGroup:
+		commandDefinitions = helpCmd.InputDefinition()
+	}
+	rawOptions, trailingGlobals := takeGlobalOptions(rawOptions, globals, commandDefinitions, false)
+	rawGlobals = append(rawGlobals, trailingGlobals...)
+	globalOptions, globalErrs := buildGlobalOptions(rawGlobals, globals)
+	noInteraction, _ := globalOptions["no-interaction"].RawVal().GetAsBool(false)
+	assumeYes, _ := globalOptions["yes"].RawVal().GetAsBool(false)
+	noColor, _ := globalOptions["no-color"].RawVal().GetAsBool(false)
+	stylerFor := func(writer io.Writer) *Styler {
+		return NewStyler(!noColor && colorsAllowed() && options.IsColorTerminal(writer))
+	}
+	stderrStyler := stylerFor(stdio.Stderr)
+	settings := runSettings{
+		prompter: NewPrompter(
+			stdio.Stdin,
+			stdio.Stderr,
+			!noInteraction && options.IsInteractive(stdio.Stdin),
+			assumeYes,
+		),
+		middlewares:   append(slices.Clone(options.Middlewares), availableCommands.middlewares...),
+		globalOptions: globalOptions,
+		output:        OutputFormat(globalOptions["output"].RawVal()),
+		stdoutStyler:  stylerFor(stdio.Stdout),
+		stderrStyler:  stderrStyler,
+	}
+	helpCmd.styler = settings.stdoutStyler
+
+	if options.Logger != nil {
+		logDefinitions := maps.Clone(globals)
+		maps.Copy(logDefinitions, commandDefinitions)
+		logCommandStart(
+			options.Logger,
+			cmdId,
+			logDefinitions,
+			append(slices.Clone(rawGlobals), rawOptions...),
+		)
+	}
+
+	switch {
+	case definitionErr != nil:
+		cmdErr = definitionErr
+	case len(globalErrs) > 0:
+		cmdErr = &UsageError{
+			fmt.Errorf("invalid global options: %w", errors.Join(globalErrs...)),
+		}
+	case isCommand && helpRequested(rawOptions, commandDefinitions):
+		writeCommandDetails(stdio.Stdout, cmd, settings.stdoutStyler)
+	case isCommand:
+		cmdErr = runCommand(ctx, cmd, rawOptions, stdio, settings)
+	case isGroup:
+		groupHelpCmd := *helpCmd
+		groupHelpCmd.group = cmdId
+		if helpRequested(rawOptions, groupHelpCmd.InputDefinition()) {
+			rawOptions = nil
+		}
+		cmdErr = runCommand(ctx, &groupHelpCmd, rawOptions, stdio, settings)
+	default:
+		cmdErr = &UsageError{unknownCommandError(cmdId, availableCommands)}
 	}
 
+	interrupted := ctx.Err() != nil
+	stopSignals()
+
+	exitCode := StatusOk
 	if cmdErr != nil {
-		_, outputErr := outputWriter.Write(
+		exitCode = exitCodeOf(cmdErr, interrupted)
+	}
+	if options.Logger != nil {
+		logCommandFinish(options.Logger, cmdId, time.Since(startTime), exitCode, cmdErr)
+	}
+
+	if cmdErr != nil {
+		_, outputErr := stdio.Stderr.Write(
 			[]byte(
-				fmt.Sprintf(
-					"Failed to execute command %s with error: %s\n",
-					cmdId,
-					cmdErr.Error(),
+				stderrStyler.Error(
+					fmt.Sprintf(
+						"Failed to execute command %s with error: %s\n",
+						cmdId,
+						errorDetails(cmdErr, options.PanicVerbosity),
+					),
 				),
 			),
 		)
 		if outputErr != nil {
-			fmt.Printf(
-				"Error writing to the provided output writer %s\n",
-				reflect.TypeOf(outputWriter),
+			_, _ = fmt.Fprintf(
+				os.Stderr,
+				"Error writing to the provided error writer %s\n",
+				reflect.TypeOf(stdio.Stderr),
 			)
 		}
-		processExit(StatusErr)
-		return
 	}
 
-	processExit(StatusOk)
+	processExit(exitCode)
 }
@@ -2,10 +2,13 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"github.com/rsgcata/gocommon/params"
 	"github.com/stretchr/testify/suite"
 	"io"
 	"testing"
+	"time"
 )
 
 type BootstrapSuite struct {
@@ -43,6 +46,15 @@ func (m *bootstrapMockCommand) Exec(options InputOptionsMap, writer io.Writer) e
 	return nil
 }
 
+// Mock command accepting undeclared options
+type lenientMockCommand struct {
+	bootstrapMockCommand
+}
+
+func (m *lenientMockCommand) AcceptsUnknownOptions() bool {
+	return true
+}
+
 func (s *BootstrapSuite) TestItCanBuildOptionsFromRawOptions() {
 	tests := []struct {
 		name        string
@@ -209,6 +221,46 @@ func (s *BootstrapSuite) TestItCanBuildOptionsFromRawOptions() {
 			},
 			wantErrors: false,
 		},
+		{
+			name:       "Valid typed options",
+			rawOptions: []string{"--port=8080", "--mode=fast", "--timeout=1m", "--ratio=0.5"},
+			cmd: &bootstrapMockCommand{
+				id:          "test",
+				description: "Test command",
+				inputDef: MustInputOptionDefinitionMap(
+					NewOption("port").Type(OptionTypeInt),
+					NewOption("mode").Enum("fast", "slow"),
+					NewOption("timeout").Type(OptionTypeDuration),
+					NewOption("ratio").Type(OptionTypeFloat),
+				),
+			},
+			wantOptions: InputOptionsMap{
+				"port":    {rawVal: "8080"},
+				"mode":    {rawVal: "fast"},
+				"timeout": {rawVal: "1m"},
+				"ratio":   {rawVal: "0.5"},
+			},
+			wantErrors: false,
+		},
+		{
+			name:       "Invalid typed options",
+			rawOptions: []string{"--port=abc", "--mode=medium", "--verbose=maybe"},
+			cmd: &bootstrapMockCommand{
+				id:          "test",
+				description: "Test command",
+				inputDef: MustInputOptionDefinitionMap(
+					NewOption("port").Type(OptionTypeInt),
+					NewOption("mode").Enum("fast", "slow"),
+					NewOption("verbose").Type(OptionTypeBool),
+				),
+			},
+			wantOptions: InputOptionsMap{
+				"port":    {rawVal: "abc"},
+				"mode":    {rawVal: "medium"},
+				"verbose": {rawVal: "maybe"},
+			},
+			wantErrors: true,
+		},
 	}
 
 	for _, scenario := range tests {
@@ -243,6 +295,280 @@ func (s *BootstrapSuite) TestItCanBuildOptionsFromRawOptions() {
 	}
 }
 
+func (s *BootstrapSuite) TestItCanBuildOptionsFromAliases() {
+	cmd := &bootstrapMockCommand{
+		id: "test",
+		inputDef: MustInputOptionDefinitionMap(
+			NewOption("port").Alias("p").Type(OptionTypeInt),
+			NewOption("host").Alias("H"),
+		),
+	}
+
+	options, errs := BuildOptionsFrom([]string{"-p", "8080", "--host", "localhost"}, cmd)
+	s.Empty(errs)
+	s.Equal(params.RawVal("8080"), options["port"].RawVal())
+	s.Equal(params.RawVal("localhost"), options["host"].RawVal())
+	s.Equal("port", options["port"].Name())
+
+	_, errs = BuildOptionsFrom([]string{"-p", "8080", "--port=9090"}, cmd)
+	s.Len(errs, 1)
+	s.Contains(errs[0].Error(), "option 'port' is defined twice")
+}
+
+func (s *BootstrapSuite) TestItCanBuildBooleanFlags() {
+	cmd := &bootstrapMockCommand{
+		id: "test",
+		inputDef: MustInputOptionDefinitionMap(
+			NewOption("force").Alias("f").Flag().Required(),
+			NewOption("cache").Flag().Default("true"),
+		),
+	}
+
+	options, errs := BuildOptionsFrom([]string{"--force", "--no-cache"}, cmd)
+	s.Empty(errs)
+	force, defaultUsed := options["force"].RawVal().GetAsBool(false)
+	s.True(force)
+	s.False(defaultUsed)
+	cache, _ := options["cache"].RawVal().GetAsBool(true)
+	s.False(cache)
+
+	options, errs = BuildOptionsFrom([]string{"-f"}, cmd)
+	s.Empty(errs)
+	force, _ = options["force"].RawVal().GetAsBool(false)
+	s.True(force)
+	s.Equal(ValueSourceDefault, options["cache"].Source())
+
+	_, errs = BuildOptionsFrom([]string{"--force", "--no-force"}, cmd)
+	s.Len(errs, 1)
+}
+
+func (s *BootstrapSuite) TestItAppliesDefaultValuesForOmittedOptions() {
+	cmd := &bootstrapMockCommand{
+		id: "test",
+		inputDef: MustInputOptionDefinitionMap(
+			NewOption("limit").Type(OptionTypeInt).Default("10"),
+			NewOption("format").Default("text"),
+			NewOption("filter"),
+		),
+	}
+
+	options, errs := BuildOptionsFrom([]string{"--format=json"}, cmd)
+
+	s.Empty(errs)
+	s.Len(options, 2)
+
+	limit, _ := options["limit"].RawVal().GetAsInt(0)
+	s.Equal(10, limit)
+	s.Equal(ValueSourceDefault, options["limit"].Source())
+
+	s.Equal(params.RawVal("json"), options["format"].RawVal())
+	s.Equal(ValueSourceUser, options["format"].Source())
+
+	_, filterSet := options["filter"]
+	s.False(filterSet, "options without default should not be filled")
+	s.Equal(ValueSourceOther, InputOption{}.Source())
+}
+
+func (s *BootstrapSuite) TestItFillsOmittedOptionsFromEnvironmentVariables() {
+	s.T().Setenv("CLI_TEST_PORT", "9090")
+	s.T().Setenv("CLI_TEST_HOST", "example.com")
+	s.T().Setenv("CLI_TEST_VERBOSE", "1")
+	s.T().Setenv("CLI_TEST_EMPTY", "  ")
+	cmd := &bootstrapMockCommand{
+		id: "test",
+		inputDef: MustInputOptionDefinitionMap(
+			NewOption("port").Type(OptionTypeInt).Env("CLI_TEST_PORT").Default("8080"),
+			NewOption("host").Env("CLI_TEST_HOST").Required(),
+			NewOption("verbose").Flag().Env("CLI_TEST_VERBOSE"),
+			NewOption("mode").Env("CLI_TEST_EMPTY").Default("fast"),
+			NewOption("user").Env("CLI_TEST_UNSET"),
+		),
+	}
+
+	options, errs := BuildOptionsFrom([]string{"--host=localhost"}, cmd)
+
+	s.Empty(errs)
+	s.Len(options, 4)
+	s.Equal(params.RawVal("9090"), options["port"].RawVal())
+	s.Equal(ValueSourceEnv, options["port"].Source())
+	s.Equal(params.RawVal("localhost"), options["host"].RawVal())
+	s.Equal(ValueSourceUser, options["host"].Source())
+	s.Equal(params.RawVal("1"), options["verbose"].RawVal())
+	s.Equal(ValueSourceEnv, options["verbose"].Source())
+	s.Equal(params.RawVal("fast"), options["mode"].RawVal())
+	s.Equal(ValueSourceDefault, options["mode"].Source())
+
+	options, errs = BuildOptionsFrom(nil, cmd)
+	s.Empty(errs, "required options can be provided through the environment")
+	s.Equal(params.RawVal("example.com"), options["host"].RawVal())
+
+	s.T().Setenv("CLI_TEST_PORT", "abc")
+	_, errs = BuildOptionsFrom(nil, cmd)
+	s.Len(errs, 1)
+	s.EqualError(
+		errs[0],
+		"environment variable CLI_TEST_PORT: option 'port' expects an integer value, got 'abc'",
+	)
+}
+
+func (s *BootstrapSuite) TestItCanBuildRepeatableAndListOptions() {
+	s.T().Setenv("CLI_TEST_WAITS", "1s, 2m")
+	cmd := &bootstrapMockCommand{
+		id: "test",
+		inputDef: MustInputOptionDefinitionMap(
+			NewOption("tag").Alias("t").Repeatable(),
+			NewOption("ids").Type(OptionTypeInt).List(),
+			NewOption("waits").Type(OptionTypeDuration).List().Env("CLI_TEST_WAITS"),
+			NewOption("names").List().Default("a,b"),
+			NewOption("name"),
+		),
+	}
+
+	options, errs := BuildOptionsFrom(
+		[]string{"--tag=x,y", "-t", "z", "--ids=1,2", "--ids", "3", "--name=n"},
+		cmd,
+	)
+
+	s.Empty(errs)
+	s.Equal([]params.RawVal{"x,y", "z"}, options["tag"].RawVals())
+	ids, defaultUsed := options["ids"].RawVal().GetAsInts(nil)
+	s.False(defaultUsed)
+	s.Equal([]int{1, 2, 3}, ids)
+	s.Equal([]params.RawVal{"1", "2", "3"}, options["ids"].RawVals())
+	waits, _ := options["waits"].RawVal().GetAsDurations(nil)
+	s.Equal([]time.Duration{time.Second, 2 * time.Minute}, waits)
+	s.Equal(ValueSourceEnv, options["waits"].Source())
+	names, _ := options["names"].RawVal().GetAsStrings(nil)
+	s.Equal([]string{"a", "b"}, names)
+	s.Equal([]params.RawVal{"n"}, options["name"].RawVals())
+	s.Empty(InputOption{}.RawVals())
+
+	_, errs = BuildOptionsFrom([]string{"--ids=1,x"}, cmd)
+	s.Len(errs, 1)
+	s.EqualError(errs[0], "option 'ids' expects an integer value, got 'x'")
+
+	_, errs = BuildOptionsFrom([]string{"--name=a", "--name=b"}, cmd)
+	s.Len(errs, 1)
+	s.EqualError(errs[0], "option 'name' is defined twice")
+}
+
+func (s *BootstrapSuite) TestItRejectsUnknownOptionsWithSuggestions() {
+	cmd := &bootstrapMockCommand{
+		id: "test",
+		inputDef: MustInputOptionDefinitionMap(
+			NewOption("dry-run").Flag(),
+			NewOption("verbose").Alias("v").Flag(),
+		),
+	}
+
+	options, errs := BuildOptionsFrom(
+		[]string{"--dryrun", "--unrelated-option", "-x", "--=x", "-=x"},
+		cmd,
+	)
+
+	s.Len(errs, 5)
+	s.Empty(options)
+	s.EqualError(errs[0], "option name is missing in '--=x'")
+	s.EqualError(errs[1], "option name is missing in '-=x'")
+	s.EqualError(errs[2], "option 'dryrun' is not defined, did you mean --dry-run?")
+	s.EqualError(errs[3], "option 'unrelated-option' is not defined")
+	s.EqualError(errs[4], "option 'x' is not defined")
+}
+
+func (s *BootstrapSuite) TestLenientCommandsAcceptUnknownOptions() {
+	cmd := &lenientMockCommand{
+		bootstrapMockCommand: bootstrapMockCommand{
+			id:       "test",
+			inputDef: MustInputOptionDefinitionMap(NewOption("name")),
+		},
+	}
+
+	options, errs := BuildOptionsFrom([]string{"--name=a", "--extra=b"}, cmd)
+
+	s.Empty(errs)
+	s.Equal(params.RawVal("a"), options["name"].RawVal())
+	s.Equal(params.RawVal("b"), options["extra"].RawVal())
+}
+
+func (s *BootstrapSuite) TestItReturnsOneErrorPerInvalidTypedOption() {
+	cmd := &bootstrapMockCommand{
+		id: "test",
+		inputDef: MustInputOptionDefinitionMap(
+			NewOption("port").Type(OptionTypeInt),
+			NewOption("mode").Enum("fast", "slow"),
+			NewOption("name"),
+		),
+	}
+
+	_, errs := BuildOptionsFrom([]string{"--port=abc", "--mode=medium", "--name=x"}, cmd)
+
+	s.Len(errs, 2)
+	joined := errors.Join(errs...).Error()
+	s.Contains(joined, "option 'port' expects an integer value, got 'abc'")
+	s.Contains(joined, "option 'mode' expects one of fast, slow, got 'medium'")
+}
+
+func (s *BootstrapSuite) TestItRejectsTypedOptionsWithoutValue() {
+	cmd := &bootstrapMockCommand{
+		id: "test",
+		inputDef: MustInputOptionDefinitionMap(
+			NewOption("port").Alias("p").Type(OptionTypeInt).Default("80"),
+			NewOption("ids").Type(OptionTypeInt).List(),
+			NewOption("name").Alias("n"),
+		),
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantError string
+	}{
+		{"Long option at the end", []string{"--port"}, "option 'port' requires a value"},
+		{"Alias at the end", []string{"-p"}, "option 'port' requires a value"},
+		{"Followed by an option", []string{"-p", "--name=x"}, "option 'port' requires a value"},
+		{"Empty value", []string{"--port="}, "option 'port' requires a value"},
+		{"Empty list", []string{"--ids="}, "option 'ids' requires a value"},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				_, errs := BuildOptionsFrom(scenario.args, cmd)
+				s.Require().Len(errs, 1)
+				s.EqualError(errs[0], scenario.wantError)
+			},
+		)
+	}
+
+	options, errs := BuildOptionsFrom([]string{"-n"}, cmd)
+	s.Empty(errs)
+	s.Equal(params.RawVal(""), options["name"].RawVal())
+	s.Equal(params.RawVal("80"), options["port"].RawVal())
+}
+
+func (s *BootstrapSuite) TestItDoesNotExecuteCommandWhenTypedOptionIsInvalid() {
+	executed := false
+	cmd := &bootstrapMockCommand{
+		id:       "test",
+		inputDef: MustInputOptionDefinitionMap(NewOption("port").Type(OptionTypeInt)),
+		execFunc: func(options InputOptionsMap, writer io.Writer) error {
+			executed = true
+			return nil
+		},
+	}
+
+	err := runCommand(
+		context.Background(),
+		cmd,
+		[]string{"--port=abc"},
+		IO{Stdout: &bytes.Buffer{}},
+		runSettings{},
+	)
+
+	s.Error(err)
+	s.False(executed, "Exec should not be called when an option is invalid")
+}
+
 func (s *BootstrapSuite) TestItCanParseCmdInput() {
 	tests := []struct {
 		name        string
@@ -465,7 +791,13 @@ func (s *BootstrapSuite) TestItCanRunCommand() {
 
 				// Create a buffer to capture output
 				var buf bytes.Buffer
-				err := runCommand(scenario.cmd, scenario.rawOptions, &buf)
+				err := runCommand(
+					context.Background(),
+					scenario.cmd,
+					scenario.rawOptions,
+					IO{Stdout: &buf},
+					runSettings{},
+				)
 
 				// Check if error is expected
 				if scenario.expectError {
@@ -541,19 +873,27 @@ func (s *BootstrapSuite) TestItCanBootstrapCliRunner() {
 				exitCode = code
 			}
 
-			// Create a buffer to capture output
-			var buf bytes.Buffer
+			// Create buffers to capture output and errors
+			var buf, errBuf bytes.Buffer
 
 			// Call Bootstrap with a non-existent command
-			Bootstrap([]string{"nonexistent"}, *registry, &buf, mockExit)
+			BootstrapWithOptions(
+				[]string{"nonexistent"},
+				*registry,
+				BootstrapOptions{
+					IO:          IO{Stdout: &buf, Stderr: &errBuf},
+					ProcessExit: mockExit,
+				},
+			)
 
 			// Verify the error was handled correctly
-			s.Equal(StatusErr, exitCode, "Bootstrap should exit with StatusErr")
+			s.Equal(StatusUsage, exitCode, "Bootstrap should exit with StatusUsage")
 			s.Contains(
-				buf.String(),
+				errBuf.String(),
 				"does not exist",
-				"Bootstrap should write error message to writer",
+				"Bootstrap should write error message to error writer",
 			)
+			s.Empty(buf.String(), "Bootstrap should not write errors to output writer")
 		},
 	)
 
@@ -577,19 +917,27 @@ func (s *BootstrapSuite) TestItCanBootstrapCliRunner() {
 				exitCode = code
 			}
 
-			// Create a buffer to capture output
-			var buf bytes.Buffer
+			// Create buffers to capture output and errors
+			var buf, errBuf bytes.Buffer
 
 			// Call Bootstrap with the test command
-			Bootstrap([]string{"test"}, *registry, &buf, mockExit)
+			BootstrapWithOptions(
+				[]string{"test"},
+				*registry,
+				BootstrapOptions{
+					IO:          IO{Stdout: &buf, Stderr: &errBuf},
+					ProcessExit: mockExit,
+				},
+			)
 
 			// Verify the error was handled correctly
 			s.Equal(StatusErr, exitCode, "Bootstrap should exit with StatusErr")
 			s.Contains(
-				buf.String(),
+				errBuf.String(),
 				"command execution error",
-				"Bootstrap should write error message to writer",
+				"Bootstrap should write error message to error writer",
 			)
+			s.Empty(buf.String(), "Bootstrap should not write errors to output writer")
 		},
 	)
 
@@ -0,0 +1,122 @@
+package clitest
+
+import (
+	"bytes"
+	"flag"
+	"github.com/rsgcata/gocommon/presentation/cli"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// DefaultProgramName is the program name used when the bootstrap options do not set one, so
+// the output does not depend on the name of the test binary
+const DefaultProgramName = "app"
+
+var update = flag.Bool("update-golden", false, "update the golden files of clitest")
+
+// Result is the outcome of running a command
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Runner runs commands the way cli.BootstrapWithOptions does for a real process, with the
+// standard streams and the process exit replaced by in memory ones
+type Runner struct {
+	registry    cli.CommandsRegistry
+	registerErr error
+	options     cli.BootstrapOptions
+	env         map[string]string
+	stdin       string
+}
+
+// NewRunner creates a runner for the commands of the registry
+func NewRunner(registry cli.CommandsRegistry) *Runner {
+	return &Runner{registry: registry, env: map[string]string{}}
+}
+
+// NewCommandRunner creates a runner for a single command. If the command cannot be
+// registered, Run fails the test.
+func NewCommandRunner(cmd cli.Command) *Runner {
+	registry := cli.NewCommandsRegistry()
+	runner := NewRunner(*registry)
+	runner.registerErr = runner.registry.Register(cmd)
+	return runner
+}
+
+// WithOptions sets the bootstrap options. IO and ProcessExit are always replaced by the
+// runner.
+func (runner *Runner) WithOptions(options cli.BootstrapOptions) *Runner {
+	runner.options = options
+	return runner
+}
+
+// WithEnv sets an environment variable for the duration of the test calling Run
+func (runner *Runner) WithEnv(name string, value string) *Runner {
+	runner.env[name] = value
+	return runner
+}
+
+// WithStdin sets the input read by the command
+func (runner *Runner) WithStdin(input string) *Runner {
+	runner.stdin = input
+	return runner
+}
+
+// Run runs the command line arguments, without the program name, like "db migrate --dry-run".
+// The environment overrides are set with t.Setenv, so Run cannot be used in parallel tests.
+func (runner *Runner) Run(t testing.TB, args ...string) Result {
+	t.Helper()
+	if runner.registerErr != nil {
+		t.Fatalf("failed to register the command: %v", runner.registerErr)
+	}
+
+	for name, value := range runner.env {
+		t.Setenv(name, value)
+	}
+
+	var stdout, stderr bytes.Buffer
+	result := Result{}
+	options := runner.options
+	options.IO = cli.IO{
+		Stdin:  strings.NewReader(runner.stdin),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	options.ProcessExit = func(code int) { result.ExitCode = code }
+	if options.ProgramName == "" {
+		options.ProgramName = DefaultProgramName
+	}
+
+	cli.BootstrapWithOptions(args, runner.registry, options)
+
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+	return result
+}
+
+// AssertGolden compares the actual output with the content of the golden file, usually
+// placed in the testdata directory. When the tests run with the -update-golden flag, the
+// golden file is written with the actual output instead.
+func AssertGolden(t testing.TB, goldenPath string, actual string) bool {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+			t.Fatalf("failed to create the golden file directory: %v", err)
+		}
+		if err := os.WriteFile(goldenPath, []byte(actual), 0o644); err != nil {
+			t.Fatalf("failed to update the golden file: %v", err)
+		}
+		return true
+	}
+
+	expected, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("failed to read the golden file, run with -update-golden to create it: %v", err)
+	}
+	return assert.Equal(t, string(expected), actual, "output differs from %s", goldenPath)
+}
@@ -0,0 +1,130 @@
+package clitest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rsgcata/gocommon/presentation/cli"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type ClitestSuite struct {
+	suite.Suite
+}
+
+func TestClitestSuite(t *testing.T) {
+	suite.Run(t, new(ClitestSuite))
+}
+
+// Mock command greeting the user read from stdin, the --greeting option or its environment
+// variable
+type greetCommand struct{}
+
+func (c *greetCommand) Id() string {
+	return "greet"
+}
+
+func (c *greetCommand) Description() string {
+	return "Greets the user"
+}
+
+func (c *greetCommand) InputDefinition() cli.InputOptionDefinitionMap {
+	return cli.MustInputOptionDefinitionMap(
+		cli.NewOption("greeting").Env("GREETING").Default("Hello"),
+	)
+}
+
+func (c *greetCommand) Exec(_ cli.InputOptionsMap, _ io.Writer) error {
+	return errors.New("greet must be executed with a context")
+}
+
+func (c *greetCommand) ExecContext(_ context.Context, invocation cli.Invocation) error {
+	name, err := io.ReadAll(invocation.Stdin)
+	if err != nil {
+		return err
+	}
+	if len(name) == 0 {
+		return cli.NewExitError(3, errors.New("nobody to greet"))
+	}
+	_, err = fmt.Fprintf(
+		invocation.Stdout,
+		"%s, %s!",
+		invocation.Options["greeting"].RawVal(),
+		name,
+	)
+	return err
+}
+
+func (s *ClitestSuite) TestItRunsCommands() {
+	tests := []struct {
+		name       string
+		runner     *Runner
+		wantStdout string
+		wantStderr string
+		wantCode   int
+	}{
+		{
+			"With stdin",
+			NewCommandRunner(&greetCommand{}).WithStdin("Alice"),
+			"Hello, Alice!",
+			"",
+			cli.StatusOk,
+		},
+		{
+			"With env",
+			NewCommandRunner(&greetCommand{}).WithStdin("Bob").WithEnv("GREETING", "Hi"),
+			"Hi, Bob!",
+			"",
+			cli.StatusOk,
+		},
+		{
+			"With error",
+			NewCommandRunner(&greetCommand{}),
+			"",
+			"Failed to execute command greet with error: nobody to greet\n",
+			3,
+		},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				result := scenario.runner.Run(s.T(), "greet")
+
+				s.Equal(scenario.wantStdout, result.Stdout)
+				s.Equal(scenario.wantStderr, result.Stderr)
+				s.Equal(scenario.wantCode, result.ExitCode)
+			},
+		)
+	}
+	s.Empty(os.Getenv("GREETING"), "env overrides are restored after the test")
+}
+
+func (s *ClitestSuite) TestItRunsRegistries() {
+	registry := cli.NewCommandsRegistry()
+	s.Require().NoError(registry.Register(&greetCommand{}))
+
+	result := NewRunner(*registry).Run(s.T(), "help")
+
+	s.Equal(cli.StatusOk, result.ExitCode)
+	s.Empty(result.Stderr)
+	AssertGolden(s.T(), filepath.Join("testdata", "help.golden"), result.Stdout)
+}
+
+func (s *ClitestSuite) TestItUpdatesGoldenFiles() {
+	goldenPath := filepath.Join(s.T().TempDir(), "nested", "output.golden")
+	*update = true
+	defer func() { *update = false }()
+
+	s.True(AssertGolden(s.T(), goldenPath, "updated output"))
+
+	*update = false
+	content, err := os.ReadFile(goldenPath)
+	s.Require().NoError(err)
+	s.Equal("updated output", string(content))
+	s.True(AssertGolden(s.T(), goldenPath, "updated output"))
+}
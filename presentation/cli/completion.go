@@ -0,0 +1,357 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// completeCommandId is the id of the hidden command called by the completion scripts
+const completeCommandId = "__complete"
+
+// Completer is implemented by commands which can suggest dynamic values during the shell
+// completion, like names of resources fetched from a database or an API
+type Completer interface {
+	Command
+	Complete(request CompletionRequest) []string
+}
+
+// CompletionRequest describes the word being completed for a Completer
+type CompletionRequest struct {
+	// Option is the name of the option whose value is completed. It is empty when a
+	// positional argument is completed.
+	Option string
+	// Arguments are the positional arguments typed before the completed word
+	Arguments []string
+	// ToComplete is the partially typed word. Candidates not starting with it are dropped.
+	ToComplete string
+}
+
+// CompletionCommand writes the shell completion script for bash, zsh or fish. The scripts
+// call the hidden __complete command to get the candidates, so they do not need to be
+// regenerated when commands change.
+type CompletionCommand struct {
+	programName string
+}
+
+func (c *CompletionCommand) Id() string {
+	return "completion"
+}
+
+func (c *CompletionCommand) Description() string {
+	return "Outputs the shell completion script for bash, zsh or fish. For example, add " +
+		"'source <(" + c.programName + " completion bash)' to ~/.bashrc"
+}
+
+func (c *CompletionCommand) InputDefinition() InputOptionDefinitionMap {
+	return InputOptionDefinitionMap{}
+}
+
+func (c *CompletionCommand) ArgumentsDefinition() InputArgumentDefinitionList {
+	return MustInputArgumentDefinitionList(
+		NewArgument("shell").Description("One of bash, zsh, fish").Required(),
+	)
+}
+
+func (c *CompletionCommand) ExecWithArguments(
+	arguments InputArgumentsMap,
+	_ InputOptionsMap,
+	writer io.Writer,
+) error {
+	var script string
+	switch shell := string(arguments["shell"].RawVal()); shell {
+	case "bash":
+		script = bashCompletionScript
+	case "zsh":
+		script = zshCompletionScript
+	case "fish":
+		script = fishCompletionScript
+	default:
+		return &UsageError{
+			fmt.Errorf("shell '%s' is not supported, expected one of bash, zsh, fish", shell),
+		}
+	}
+
+	_, err := io.WriteString(
+		writer,
+		strings.NewReplacer(
+			"{{program}}", c.programName,
+			"{{function}}", shellFunctionName(c.programName),
+		).Replace(script),
+	)
+	return err
+}
+
+func (c *CompletionCommand) Exec(_ InputOptionsMap, _ io.Writer) error {
+	return &UsageError{fmt.Errorf("argument 'shell' is required")}
+}
+
+var shellFunctionNamePattern = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// shellFunctionName builds a valid shell function name from the program name
+func shellFunctionName(programName string) string {
+	return "__" + shellFunctionNamePattern.ReplaceAllString(programName, "_") + "_complete"
+}
+
+const bashCompletionScript = `# bash completion for {{program}}
+{{function}}() {
+    local cur words cword
+    if declare -F _get_comp_words_by_ref >/dev/null 2>&1; then
+        _get_comp_words_by_ref -n =: cur words cword
+    else
+        # COMP_WORDS splits "--name=value" on "=", the words are rebuilt from the line instead
+        local line="${COMP_LINE:0:COMP_POINT}"
+        read -ra words <<< "$line"
+        if [[ -z "$line" || "$line" == *[[:space:]] ]]; then
+            words+=("")
+        fi
+        cword=$((${#words[@]} - 1))
+        cur="${words[cword]}"
+    fi
+
+    local IFS=$'\n'
+    COMPREPLY=($({{program}} __complete -- "${words[@]:1:cword-1}" "$cur" 2>/dev/null))
+
+    # Readline replaces only the text after the last "=", like __ltrim_colon_completions for ":"
+    if [[ "$cur" == *=* && "$COMP_WORDBREAKS" == *=* ]]; then
+        local prefix="${cur%"${cur##*=}"}"
+        COMPREPLY=("${COMPREPLY[@]#"$prefix"}")
+    fi
+}
+complete -o default -F {{function}} {{program}}
+`
+
+const zshCompletionScript = `#compdef {{program}}
+{{function}}() {
+    local -a candidates
+    local output
+    output="$({{program}} __complete -- "${(@)words[2,CURRENT-1]}" "${words[CURRENT]}" 2>/dev/null)"
+    candidates=("${(@f)output}")
+    candidates=(${candidates:#})
+    compadd -- "${candidates[@]}"
+}
+compdef {{function}} {{program}}
+`
+
+const fishCompletionScript = `# fish completion for {{program}}
+function {{function}}
+    set -l tokens (commandline -opc)
+    {{program}} __complete -- $tokens[2..-1] (commandline -ct) 2>/dev/null
+end
+complete -c {{program}} -f -a '({{function}})'
+`
+
+// completeCommand is the hidden command called by the completion scripts. It receives the
+// words typed after the program name, the last one being the word to complete, and writes
+// the candidates one per line. Global options are completed along with the command options.
+type completeCommand struct {
+	registry *CommandsRegistry
+	globals  InputOptionDefinitionMap
+}
+
+func (c *completeCommand) Id() string {
+	return completeCommandId
+}
+
+func (c *completeCommand) Description() string {
+	return "Outputs the shell completion candidates for the provided words"
+}
+
+func (c *completeCommand) InputDefinition() InputOptionDefinitionMap {
+	return InputOptionDefinitionMap{}
+}
+
+func (c *completeCommand) Hidden() bool {
+	return true
+}
+
+func (c *completeCommand) ArgumentsDefinition() InputArgumentDefinitionList {
+	return MustInputArgumentDefinitionList(NewArgument("words").Variadic())
+}
+
+func (c *completeCommand) ExecWithArguments(
+	arguments InputArgumentsMap,
+	_ InputOptionsMap,
+	writer io.Writer,
+) error {
+	var words []string
+	for _, word := range arguments["words"].RawVals() {
+		words = append(words, string(word))
+	}
+
+	toComplete := ""
+	if len(words) > 0 {
+		words, toComplete = words[:len(words)-1], words[len(words)-1]
+	}
+
+	for _, candidate := range c.candidates(words, toComplete) {
+		if _, err := fmt.Fprintln(writer, candidate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *completeCommand) Exec(options InputOptionsMap, writer io.Writer) error {
+	return c.ExecWithArguments(InputArgumentsMap{}, options, writer)
+}
+
+// candidates returns the sorted completion candidates for the word being typed: subcommands,
+// option names, option values or positional argument values. The global options typed before
+// the command are skipped, or completed when no command was typed yet.
+func (c *completeCommand) candidates(words []string, toComplete string) []string {
+	var candidates []string
+	words, rest := takeGlobalOptions(words, c.globals, nil, true)
+	cmdId, consumed, isCommand, _ := c.registry.resolve(words)
+	if !isCommand && len(words) > 0 {
+		if !slices.ContainsFunc(
+			words, func(word string) bool {
+				return strings.HasPrefix(word, "-")
+			},
+		) {
+			candidates = c.subcommands(words)
+		}
+		return filterCandidates(candidates, toComplete)
+	}
+
+	var cmd Command
+	definitions := c.globals
+	if isCommand {
+		cmd, _ = c.registry.Command(cmdId)
+		rest = words[consumed:]
+		definitions = withGlobalDefinitions(cmd.InputDefinition(), c.globals)
+	}
+	index := definitions.index()
+
+	if name, value, hasValue := strings.Cut(toComplete, "="); hasValue &&
+		strings.HasPrefix(name, "--") {
+		if def, known := index[name[2:]]; known && !def.IsFlag() {
+			for _, candidate := range optionValues(cmd, definitions, def, rest, value) {
+				candidates = append(candidates, name+"="+candidate)
+			}
+		}
+		return filterCandidates(candidates, toComplete)
+	}
+
+	if len(rest) > 0 {
+		if def, expectsValue := optionExpectingValue(rest[len(rest)-1], index); expectsValue {
+			return filterCandidates(
+				optionValues(cmd, definitions, def, rest[:len(rest)-1], toComplete),
+				toComplete,
+			)
+		}
+	}
+
+	if strings.HasPrefix(toComplete, "-") {
+		for _, def := range sortedOptionDefinitions(definitions) {
+			candidates = append(candidates, optionNames(def)...)
+		}
+		if _, declared := index["help"]; !declared {
+			candidates = append(candidates, "--help")
+		}
+		return filterCandidates(candidates, toComplete)
+	}
+
+	if !isCommand {
+		return filterCandidates(c.subcommands(nil), toComplete)
+	}
+	if len(rest) == 0 {
+		candidates = c.subcommands(words[:consumed])
+	}
+	if completer, ok := cmd.(Completer); ok {
+		candidates = append(
+			candidates,
+			completer.Complete(
+				CompletionRequest{
+					Arguments:  parseArgs(rest, definitions).positional,
+					ToComplete: toComplete,
+				},
+			)...,
+		)
+	}
+	return filterCandidates(candidates, toComplete)
+}
+
+// subcommands returns the next word of the visible command paths starting with the words
+func (c *completeCommand) subcommands(words []string) []string {
+	var subcommands []string
+	for _, path := range c.registry.visiblePaths() {
+		pathWords := strings.Fields(path)
+		if len(pathWords) > len(words) && slices.Equal(pathWords[:len(words)], words) {
+			subcommands = append(subcommands, pathWords[len(words)])
+		}
+	}
+	return subcommands
+}
+
+// optionExpectingValue checks if the word is a known option which takes its value from the
+// next word, like "--port" or "-p"
+func optionExpectingValue(
+	word string,
+	index map[string]InputOptionDefinition,
+) (InputOptionDefinition, bool) {
+	var name string
+	switch {
+	case strings.HasPrefix(word, "--") && !strings.Contains(word, "="):
+		name = word[2:]
+	case strings.HasPrefix(word, "-") && len(word) == 2:
+		name = word[1:]
+	default:
+		return InputOptionDefinition{}, false
+	}
+
+	def, known := index[name]
+	return def, known && !def.IsFlag()
+}
+
+// optionValues returns the enum values of the option along with the values suggested by the
+// command, if it is a Completer
+func optionValues(
+	cmd Command,
+	definitions InputOptionDefinitionMap,
+	def InputOptionDefinition,
+	rest []string,
+	toComplete string,
+) []string {
+	values := def.EnumValues()
+	if completer, ok := cmd.(Completer); ok {
+		values = append(
+			values,
+			completer.Complete(
+				CompletionRequest{
+					Option:     def.name,
+					Arguments:  parseArgs(rest, definitions).positional,
+					ToComplete: toComplete,
+				},
+			)...,
+		)
+	}
+	return values
+}
+
+// optionNames returns the names under which the option can be typed, like "--force", "-f"
+// and "--no-force"
+func optionNames(def InputOptionDefinition) []string {
+	names := []string{optionFlagName(def.name)}
+	for _, alias := range def.aliases {
+		names = append(names, optionFlagName(alias))
+	}
+	if def.isNegatable() {
+		names = append(names, "--no-"+def.name)
+	}
+	return names
+}
+
+// filterCandidates keeps the unique candidates starting with the typed word, sorted
+func filterCandidates(candidates []string, toComplete string) []string {
+	var filtered []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, toComplete) {
+			filtered = append(filtered, candidate)
+		}
+	}
+	slices.Sort(filtered)
+	return slices.Compact(filtered)
+}
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type CompletionSuite struct {
+	suite.Suite
+}
+
+func TestCompletionSuite(t *testing.T) {
+	suite.Run(t, new(CompletionSuite))
+}
+
+// Mock command suggesting dynamic values during the shell completion
+type completerMockCommand struct {
+	bootstrapMockCommand
+	requests []CompletionRequest
+}
+
+func (m *completerMockCommand) Complete(request CompletionRequest) []string {
+	m.requests = append(m.requests, request)
+	if request.Option == "" {
+		return []string{"users.csv", "orders.csv"}
+	}
+	if request.Option == "region" {
+		return []string{"eu-west", "us-east"}
+	}
+	return nil
+}
+
+// Mock command which is not listed by the help and the completion
+type hiddenMockCommand struct {
+	bootstrapMockCommand
+}
+
+func (m *hiddenMockCommand) Hidden() bool {
+	return true
+}
+
+func (s *CompletionSuite) createRegistry() (*CommandsRegistry, *completerMockCommand) {
+	registry := NewCommandsRegistry()
+	importCmd := &completerMockCommand{
+		bootstrapMockCommand: bootstrapMockCommand{
+			id: "import",
+			inputDef: MustInputOptionDefinitionMap(
+				NewOption("region").Alias("r"),
+				NewOption("format").Enum("csv", "json"),
+				NewOption("force").Flag(),
+				NewOption("no-cache").Flag(),
+			),
+		},
+	}
+	_ = registry.Register(importCmd)
+	_ = registry.Register(&bootstrapMockCommand{id: "db migrate up"})
+	_ = registry.Register(&bootstrapMockCommand{id: "db migrate down"})
+	_ = registry.Register(&bootstrapMockCommand{id: "db seed"})
+	_ = registry.Register(&hiddenMockCommand{bootstrapMockCommand{id: "debug"}})
+	return registry, importCmd
+}
+
+func (s *CompletionSuite) complete(registry *CommandsRegistry, words ...string) []string {
+	var stdout bytes.Buffer
+	var exitCode int
+	BootstrapWithOptions(
+		append([]string{completeCommandId, "--"}, words...),
+		*registry,
+		BootstrapOptions{
+			IO:          IO{Stdout: &stdout, Stderr: &bytes.Buffer{}},
+			ProcessExit: func(code int) { exitCode = code },
+		},
+	)
+	s.Equal(StatusOk, exitCode)
+	return strings.Fields(stdout.String())
+}
+
+func (s *CompletionSuite) TestItCompletesCommandsOptionsAndValues() {
+	registry, _ := s.createRegistry()
+
+	tests := []struct {
+		name  string
+		words []string
+		want  []string
+	}{
+		{"Root commands", []string{""}, []string{"completion", "db", "help", "import"}},
+		{"Root prefix", []string{"d"}, []string{"db"}},
+		{"Subcommands", []string{"db", ""}, []string{"migrate", "seed"}},
+		{"Nested subcommands", []string{"db", "migrate", "u"}, []string{"up"}},
+		{
+			"Option names",
+			[]string{"import", "--"},
+			[]string{"--force", "--format", "--help", "--no-cache", "--no-color", "--no-force",
+				"--no-interaction", "--no-yes", "--output", "--region", "--yes"},
+		},
+		{"Short option names", []string{"import", "-"}, []string{"--force", "--format", "--help",
+			"--no-cache", "--no-color", "--no-force", "--no-interaction", "--no-yes", "--output",
+			"--region", "--yes", "-r"}},
+		{"Enum values", []string{"import", "--format", ""}, []string{"csv", "json"}},
+		{"Enum values with equals", []string{"import", "--format=j"}, []string{"--format=json"}},
+		{"Dynamic option values", []string{"import", "-r", "eu"}, []string{"eu-west"}},
+		{"Dynamic argument values", []string{"import", "--force", ""}, []string{"orders.csv",
+			"users.csv"}},
+		{"Unknown command", []string{"unknown", ""}, []string{}},
+		{"Help shell argument", []string{"help", "--f"}, []string{"--format"}},
+		{"Global option names", []string{"--o"}, []string{"--output"}},
+		{"Global option values", []string{"--output", ""}, []string{"csv", "json", "table",
+			"yaml"}},
+		{"Commands after global options", []string{"--output", "json", ""}, []string{
+			"completion", "db", "help", "import"}},
+		{"Subcommands after global options", []string{"--yes", "db", ""}, []string{"migrate",
+			"seed"}},
+		{"Global option values after the command", []string{"import", "--output=y"},
+			[]string{"--output=yaml"}},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				s.Equal(scenario.want, s.complete(registry, scenario.words...))
+			},
+		)
+	}
+}
+
+func (s *CompletionSuite) TestCompleterReceivesTheCompletionRequest() {
+	registry, importCmd := s.createRegistry()
+
+	s.complete(registry, "import", "a.csv", "--region", "e")
+	s.complete(registry, "import", "a.csv", "--force", "b")
+	s.complete(registry, "import", "--output", "json", "a.csv", "c")
+
+	s.Equal(
+		[]CompletionRequest{
+			{Option: "region", Arguments: []string{"a.csv"}, ToComplete: "e"},
+			{Arguments: []string{"a.csv"}, ToComplete: "b"},
+			{Arguments: []string{"a.csv"}, ToComplete: "c"},
+		},
+		importCmd.requests,
+	)
+}
+
+func (s *CompletionSuite) TestItGeneratesCompletionScripts() {
+	registry, _ := s.createRegistry()
+
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		s.Run(
+			shell, func() {
+				var stdout bytes.Buffer
+				var exitCode int
+				BootstrapWithOptions(
+					[]string{"completion", shell},
+					*registry,
+					BootstrapOptions{
+						IO:          IO{Stdout: &stdout, Stderr: &bytes.Buffer{}},
+						ProcessExit: func(code int) { exitCode = code },
+						ProgramName: "my-app",
+					},
+				)
+
+				s.Equal(StatusOk, exitCode)
+				s.Contains(stdout.String(), "my-app __complete --")
+				s.Contains(stdout.String(), "__my_app_complete")
+				s.NotContains(stdout.String(), "{{")
+			},
+		)
+	}
+
+	var exitCode int
+	BootstrapWithOptions(
+		[]string{"completion", "powershell"},
+		*registry,
+		BootstrapOptions{
+			IO:          IO{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}},
+			ProcessExit: func(code int) { exitCode = code },
+		},
+	)
+	s.Equal(StatusUsage, exitCode)
+}
+
+func (s *CompletionSuite) TestBashScriptKeepsOnlyTheValueAfterTheEqualSign() {
+	registry, _ := s.createRegistry()
+
+	var stdout bytes.Buffer
+	BootstrapWithOptions(
+		[]string{"completion", "bash"},
+		*registry,
+		BootstrapOptions{
+			IO:          IO{Stdout: &stdout, Stderr: &bytes.Buffer{}},
+			ProcessExit: func(int) {},
+			ProgramName: "my-app",
+		},
+	)
+
+	s.Contains(stdout.String(), `local line="${COMP_LINE:0:COMP_POINT}"`)
+	s.Contains(stdout.String(), `COMPREPLY=("${COMPREPLY[@]#"$prefix"}")`)
+}
+
+func (s *CompletionSuite) TestHiddenCommandsAreNotListedInHelp() {
+	registry, _ := s.createRegistry()
+
+	var stdout bytes.Buffer
+	BootstrapWithOptions(
+		[]string{"help"},
+		*registry,
+		BootstrapOptions{
+			IO:          IO{Stdout: &stdout, Stderr: &bytes.Buffer{}},
+			ProcessExit: func(int) {},
+		},
+	)
+
+	s.Contains(stdout.String(), "completion")
+	s.NotContains(stdout.String(), "debug")
+	s.NotContains(stdout.String(), completeCommandId)
+	_, registered := registry.Command("completion")
+	s.False(registered, "built-in commands should not be added to the caller registry")
+}
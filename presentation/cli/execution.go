@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultGraceTimeout is how long Bootstrap waits, after the first interrupt signal, for a
+// ContextCommand to return before forcing the process to exit
+const DefaultGraceTimeout = 10 * time.Second
+
+// IO holds the standard streams of a command execution. Command output goes to Stdout, errors
+// and diagnostics go to Stderr, so the output can be piped safely.
+type IO struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// StdIO returns the process standard streams
+func StdIO() IO {
+	return IO{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+}
+
+// withDefaults fills the missing streams with the process standard streams
+func (stdio IO) withDefaults() IO {
+	if stdio.Stdin == nil {
+		stdio.Stdin = os.Stdin
+	}
+	if stdio.Stdout == nil {
+		stdio.Stdout = os.Stdout
+	}
+	if stdio.Stderr == nil {
+		stdio.Stderr = os.Stderr
+	}
+	return stdio
+}
+
+// Invocation holds everything a ContextCommand receives when it is executed
+type Invocation struct {
+	IO
+	// Command is the command being executed
+	Command   Command
+	Options   InputOptionsMap
+	Arguments InputArgumentsMap
+	// Prompter asks the user for input, like confirmations before destructive actions
+	Prompter *Prompter
+	// Output is the format requested with the --output option, used to render the results
+	// of ResultCommands. Other commands can use it to format their own output.
+	Output OutputFormat
+	// StdoutStyler and StderrStyler color the text written to Stdout and Stderr, like
+	// warning or success messages. They leave the text unchanged when the stream is not a
+	// terminal or colors are disabled.
+	StdoutStyler *Styler
+	StderrStyler *Styler
+}
+
+// ContextCommand is implemented by commands which need a context.Context, for example to stop
+// long-running work cleanly. Bootstrap calls ExecContext instead of Exec (or
+// ExecWithArguments) for these commands, with a context cancelled when the process receives
+// SIGINT or SIGTERM. Positional arguments declared through ArgumentsDefinition, if the
+// command also has that method, are passed in Invocation.Arguments.
+type ContextCommand interface {
+	Command
+	ExecContext(ctx context.Context, invocation Invocation) error
+}
+
+// interruptSignals are the signals which cancel the command context
+var interruptSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// watchSignals cancels the command context when the first signal is received. After that, a
+// second signal or the grace timeout expiring calls forceExit. A zero or negative grace
+// timeout waits for the command indefinitely. It returns when done is closed.
+func watchSignals(
+	signals <-chan os.Signal,
+	cancel context.CancelFunc,
+	graceTimeout time.Duration,
+	forceExit func(),
+	done <-chan struct{},
+) {
+	select {
+	case <-signals:
+		cancel()
+	case <-done:
+		return
+	}
+
+	var timeout <-chan time.Time
+	if graceTimeout > 0 {
+		timer := time.NewTimer(graceTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-signals:
+		forceExit()
+	case <-timeout:
+		forceExit()
+	case <-done:
+	}
+}
+
+// handlesCancellation reports if the command receives the context, so it can stop cleanly when
+// the context is cancelled
+func handlesCancellation(cmd Command) bool {
+	switch cmd.(type) {
+	case ContextCommand, ResultCommand:
+		return true
+	default:
+		return false
+	}
+}
+
+// watchFirstSignal calls forceExit on the first signal. It is used for the commands which do
+// not receive the context, so they are stopped right away like without signal handlers. It
+// returns when done is closed.
+func watchFirstSignal(signals <-chan os.Signal, forceExit func(), done <-chan struct{}) {
+	select {
+	case <-signals:
+		forceExit()
+	case <-done:
+	}
+}
+
+// signalContext returns a context cancelled on SIGINT or SIGTERM. When graceful is false, the
+// first signal forces the exit instead. The returned stop function must be called once the
+// command finished, to release the signal handlers. Calling it again has no effect.
+func signalContext(graceTimeout time.Duration, graceful bool, forceExit func()) (
+	ctx context.Context,
+	stop func(),
+) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, interruptSignals...)
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		if graceful {
+			watchSignals(signals, cancel, graceTimeout, forceExit, done)
+		} else {
+			watchFirstSignal(signals, forceExit, done)
+		}
+	}()
+
+	var stopOnce sync.Once
+	return ctx, func() {
+		stopOnce.Do(
+			func() {
+				signal.Stop(signals)
+				close(done)
+				<-finished
+				cancel()
+			},
+		)
+	}
+}
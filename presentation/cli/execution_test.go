@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ExecutionSuite struct {
+	suite.Suite
+}
+
+func TestExecutionSuite(t *testing.T) {
+	suite.Run(t, new(ExecutionSuite))
+}
+
+// Mock command receiving a context
+type contextMockCommand struct {
+	bootstrapMockCommand
+	argumentsDef    InputArgumentDefinitionList
+	execContextFunc func(ctx context.Context, invocation Invocation) error
+}
+
+func (m *contextMockCommand) ArgumentsDefinition() InputArgumentDefinitionList {
+	return m.argumentsDef
+}
+
+func (m *contextMockCommand) ExecContext(ctx context.Context, invocation Invocation) error {
+	if m.execContextFunc != nil {
+		return m.execContextFunc(ctx, invocation)
+	}
+	return nil
+}
+
+func (s *ExecutionSuite) TestItCallsExecContextForContextCommands() {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	var gotInvocation Invocation
+	var gotCtxValue any
+	cmd := &contextMockCommand{
+		bootstrapMockCommand: bootstrapMockCommand{
+			id:       "test",
+			inputDef: MustInputOptionDefinitionMap(NewOption("name")),
+		},
+		argumentsDef: MustInputArgumentDefinitionList(NewArgument("file")),
+		execContextFunc: func(ctx context.Context, invocation Invocation) error {
+			gotCtxValue = ctx.Value(ctxKey{})
+			gotInvocation = invocation
+			_, _ = invocation.Stdout.Write([]byte("done"))
+			return nil
+		},
+	}
+
+	var buf bytes.Buffer
+	err := runCommand(ctx, cmd, []string{"--name=x", "a.txt"}, IO{Stdout: &buf}, runSettings{})
+
+	s.NoError(err)
+	s.Equal("value", gotCtxValue)
+	s.Equal("x", string(gotInvocation.Options["name"].RawVal()))
+	s.Equal("a.txt", string(gotInvocation.Arguments["file"].RawVal()))
+	s.Equal("done", buf.String())
+}
+
+func (s *ExecutionSuite) TestItCancelsContextOnFirstSignalAndForcesExitOnSecond() {
+	signals := make(chan os.Signal, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	forced := make(chan struct{})
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+		watchSignals(signals, cancel, time.Hour, func() { close(forced) }, done)
+	}()
+
+	signals <- os.Interrupt
+	<-ctx.Done()
+	signals <- os.Interrupt
+
+	select {
+	case <-forced:
+	case <-time.After(time.Second):
+		s.Fail("second signal should force the exit")
+	}
+	close(done)
+	<-finished
+}
+
+func (s *ExecutionSuite) TestItForcesExitWhenGraceTimeoutExpires() {
+	signals := make(chan os.Signal, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	forced := make(chan struct{})
+	done := make(chan struct{})
+
+	go watchSignals(signals, cancel, 10*time.Millisecond, func() { close(forced) }, done)
+
+	signals <- os.Interrupt
+	<-ctx.Done()
+
+	select {
+	case <-forced:
+	case <-time.After(time.Second):
+		s.Fail("grace timeout should force the exit")
+	}
+	close(done)
+}
+
+func (s *ExecutionSuite) TestItDoesNotForceExitWhenCommandFinishes() {
+	signals := make(chan os.Signal, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	forcedExit := false
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+		watchSignals(signals, cancel, time.Hour, func() { forcedExit = true }, done)
+	}()
+
+	close(done)
+	<-finished
+	s.NoError(ctx.Err())
+	s.False(forcedExit)
+}
+
+func (s *ExecutionSuite) TestBootstrapCancelsContextOnInterruptSignal() {
+	registry := NewCommandsRegistry()
+	_ = registry.Register(
+		&contextMockCommand{
+			bootstrapMockCommand: bootstrapMockCommand{
+				id:       "long",
+				inputDef: InputOptionDefinitionMap{},
+			},
+			execContextFunc: func(ctx context.Context, invocation Invocation) error {
+				process, err := os.FindProcess(os.Getpid())
+				s.Require().NoError(err)
+				s.Require().NoError(process.Signal(os.Interrupt))
+
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(5 * time.Second):
+					return nil
+				}
+			},
+		},
+	)
+
+	var exitCodes []int
+	var buf bytes.Buffer
+	BootstrapWithOptions(
+		[]string{"long"},
+		*registry,
+		BootstrapOptions{
+			IO:           IO{Stderr: &buf},
+			ProcessExit:  func(code int) { exitCodes = append(exitCodes, code) },
+			GraceTimeout: time.Minute,
+		},
+	)
+
+	s.Equal([]int{StatusInterrupted}, exitCodes)
+	s.Contains(buf.String(), "context canceled")
+}
+
+func (s *ExecutionSuite) TestBootstrapForcesExitOfPlainCommandsOnFirstSignal() {
+	exited := make(chan struct{})
+	registry := NewCommandsRegistry()
+	_ = registry.Register(
+		&bootstrapMockCommand{
+			id:       "sleep",
+			inputDef: InputOptionDefinitionMap{},
+			execFunc: func(_ InputOptionsMap, _ io.Writer) error {
+				process, err := os.FindProcess(os.Getpid())
+				s.Require().NoError(err)
+				s.Require().NoError(process.Signal(os.Interrupt))
+
+				select {
+				case <-exited:
+				case <-time.After(5 * time.Second):
+					s.Fail("the first signal should force the exit")
+				}
+				return nil
+			},
+		},
+	)
+
+	var exitCodes []int
+	var buf bytes.Buffer
+	BootstrapWithOptions(
+		[]string{"sleep"},
+		*registry,
+		BootstrapOptions{
+			IO: IO{Stderr: &buf},
+			ProcessExit: func(code int) {
+				exitCodes = append(exitCodes, code)
+				close(exited)
+			},
+			GraceTimeout: time.Minute,
+		},
+	)
+
+	s.Equal([]int{StatusInterrupted}, exitCodes)
+	s.Contains(buf.String(), "Forced exit of command sleep")
+}
+
+func (s *ExecutionSuite) TestBootstrapPassesSeparateStreamsToCommands() {
+	registry := NewCommandsRegistry()
+	_ = registry.Register(
+		&contextMockCommand{
+			bootstrapMockCommand: bootstrapMockCommand{
+				id:       "echo",
+				inputDef: InputOptionDefinitionMap{},
+			},
+			execContextFunc: func(ctx context.Context, invocation Invocation) error {
+				input, err := io.ReadAll(invocation.Stdin)
+				s.Require().NoError(err)
+				_, _ = invocation.Stdout.Write(input)
+				_, _ = invocation.Stderr.Write([]byte("diagnostics"))
+				return nil
+			},
+		},
+	)
+
+	var stdout, stderr bytes.Buffer
+	var exitCode int
+	BootstrapWithOptions(
+		[]string{"echo"},
+		*registry,
+		BootstrapOptions{
+			IO: IO{
+				Stdin:  strings.NewReader("input data"),
+				Stdout: &stdout,
+				Stderr: &stderr,
+			},
+			ProcessExit: func(code int) { exitCode = code },
+		},
+	)
+
+	s.Equal(StatusOk, exitCode)
+	s.Equal("input data", stdout.String())
+	s.Equal("diagnostics", stderr.String())
+}
+
+func (s *ExecutionSuite) TestStdIOUsesProcessStreams() {
+	s.Equal(IO{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}, StdIO())
+	s.Equal(StdIO(), IO{}.withDefaults())
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StatusUsage is used for command line usage errors, like unknown commands, options or
+// invalid values, following the sysexits convention of shells (see UsageError)
+const StatusUsage = 2
+
+// ExitCoder is implemented by errors which choose the process exit code. Errors returned by
+// commands are searched with errors.As, so the ExitCoder can be wrapped. Codes outside the
+// 1-255 range are replaced by StatusErr.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// ExitError attaches an exit code to an error returned by a command
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func NewExitError(code int, err error) *ExitError {
+	return &ExitError{Code: code, Err: err}
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// UsageError is returned when the command line input is invalid. Its exit code is
+// StatusUsage.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+func (e *UsageError) ExitCode() int {
+	return StatusUsage
+}
+
+// ExitCodesCommand is implemented by commands which document the exit codes they use. The
+// codes are listed in help output.
+type ExitCodesCommand interface {
+	Command
+	ExitCodes() map[int]string
+}
+
+// exitCodesOf returns the exit codes documented by the command, if any
+func exitCodesOf(cmd Command) map[int]string {
+	if exitCodesCmd, ok := cmd.(ExitCodesCommand); ok {
+		return exitCodesCmd.ExitCodes()
+	}
+	return nil
+}
+
+// exitCodeOf returns the exit code for an error returned by a command
+func exitCodeOf(err error, interrupted bool) int {
+	if err == nil {
+		return StatusOk
+	}
+
+	var exitCoder ExitCoder
+	if errors.As(err, &exitCoder) {
+		if code := exitCoder.ExitCode(); code > 0 && code < 256 {
+			return code
+		}
+		return StatusErr
+	}
+
+	if interrupted {
+		return StatusInterrupted
+	}
+	return StatusErr
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"testing"
+)
+
+type ExitSuite struct {
+	suite.Suite
+}
+
+func TestExitSuite(t *testing.T) {
+	suite.Run(t, new(ExitSuite))
+}
+
+// Mock command documenting its exit codes
+type exitCodesMockCommand struct {
+	mockCommand
+	exitCodes map[int]string
+}
+
+func (m *exitCodesMockCommand) ExitCodes() map[int]string {
+	return m.exitCodes
+}
+
+func (s *ExitSuite) TestItCanDetermineExitCodes() {
+	tests := []struct {
+		name        string
+		err         error
+		interrupted bool
+		want        int
+	}{
+		{"No error", nil, false, StatusOk},
+		{"Plain error", errors.New("failed"), false, StatusErr},
+		{"Exit error", NewExitError(3, errors.New("not found")), false, 3},
+		{
+			"Wrapped exit error",
+			fmt.Errorf("wrapped: %w", NewExitError(4, errors.New("invalid"))),
+			false,
+			4,
+		},
+		{"Usage error", &UsageError{errors.New("bad option")}, false, StatusUsage},
+		{"Exit code out of range", NewExitError(300, nil), false, StatusErr},
+		{"Zero exit code", NewExitError(0, errors.New("failed")), false, StatusErr},
+		{"Interrupted", errors.New("context canceled"), true, StatusInterrupted},
+		{"Interrupted with exit error", NewExitError(5, nil), true, 5},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				s.Equal(scenario.want, exitCodeOf(scenario.err, scenario.interrupted))
+			},
+		)
+	}
+}
+
+func (s *ExitSuite) TestExitErrorWrapsTheOriginalError() {
+	original := errors.New("not found")
+	err := NewExitError(3, original)
+
+	s.Equal("not found", err.Error())
+	s.ErrorIs(err, original)
+	s.Equal("exit status 3", NewExitError(3, nil).Error())
+}
+
+func (s *ExitSuite) TestBootstrapExitsWithCommandDefinedCodes() {
+	registry := NewCommandsRegistry()
+	_ = registry.Register(
+		&bootstrapMockCommand{
+			id:       "find",
+			inputDef: MustInputOptionDefinitionMap(NewOption("limit").Type(OptionTypeInt)),
+			execFunc: func(options InputOptionsMap, writer io.Writer) error {
+				return NewExitError(3, errors.New("record not found"))
+			},
+		},
+	)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantErr  string
+	}{
+		{"Command exit code", []string{"find"}, 3, "record not found"},
+		{"Invalid option value", []string{"find", "--limit=x"}, StatusUsage, "limit"},
+		{"Unknown command", []string{"unknown"}, StatusUsage, "does not exist"},
+		{"Stray positional", []string{"find", "stray"}, StatusUsage, "too many arguments"},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				var exitCode int
+				var stderr bytes.Buffer
+				BootstrapWithOptions(
+					scenario.args,
+					*registry,
+					BootstrapOptions{
+						IO:          IO{Stdout: io.Discard, Stderr: &stderr},
+						ProcessExit: func(code int) { exitCode = code },
+					},
+				)
+
+				s.Equal(scenario.wantCode, exitCode)
+				s.Contains(stderr.String(), scenario.wantErr)
+			},
+		)
+	}
+}
+
+func (s *ExitSuite) TestHelpListsDocumentedExitCodes() {
+	cmd := &HelpCommand{
+		availableCommands: []Command{
+			&exitCodesMockCommand{
+				mockCommand: mockCommand{id: "find", description: "Find records"},
+				exitCodes:   map[int]string{4: "Invalid filter", 3: "Record not found"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	s.NoError(cmd.Exec(InputOptionsMap{}, &buf))
+	output := buf.String()
+
+	s.Contains(output, "Exit codes:")
+	s.Contains(output, "3 Record not found")
+	s.Contains(output, "4 Invalid filter")
+	s.Less(bytes.Index(buf.Bytes(), []byte("3 Record")), bytes.Index(buf.Bytes(), []byte("4 Inv")))
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// builtinGlobalOptions are the global options handled by Bootstrap itself
+func builtinGlobalOptions() InputOptionDefinitionMap {
+	formats := make([]string, len(outputFormats))
+	for i, format := range outputFormats {
+		formats[i] = string(format)
+	}
+
+	return MustInputOptionDefinitionMap(
+		NewOption("no-interaction").
+			Description("Do not ask for missing options or confirmations").
+			Flag(),
+		NewOption("yes").Description("Accept all confirmations").Flag(),
+		NewOption("no-color").Description("Disable colored output").Flag(),
+		NewOption("output").
+			Description("The format of the command results").
+			Enum(formats...).
+			Default(formats[0]),
+	)
+}
+
+// globalOptionDefinitions merges the global options declared by the application with the
+// built-in ones. Declared options replace the built-in options with the same name.
+func globalOptionDefinitions(declared InputOptionDefinitionMap) InputOptionDefinitionMap {
+	definitions := builtinGlobalOptions()
+	maps.Copy(definitions, declared)
+	return definitions
+}
+
+// takeGlobalOptions moves the global options, with their separate values, from the raw
+// options to the taken ones, up to the "--" separator. Options declared by the command
+// (shadowing) are left to the command. When leadingOnly is set, it stops at the first raw
+// option which is not a global option, which is used for the options typed before the
+// command id. Grouped single character aliases ("-abc") are not recognized.
+func takeGlobalOptions(
+	rawOptions []string,
+	globals InputOptionDefinitionMap,
+	shadowing InputOptionDefinitionMap,
+	leadingOnly bool,
+) (remaining []string, taken []string) {
+	globalIndex := globals.index()
+	shadowIndex := shadowing.index()
+
+	for i := 0; i < len(rawOptions); i++ {
+		arg := rawOptions[i]
+		consumesNext, isGlobal := globalOption(arg, globalIndex, shadowIndex)
+		if arg == "--" || (!isGlobal && leadingOnly) {
+			remaining = append(remaining, rawOptions[i:]...)
+			break
+		}
+		if !isGlobal {
+			remaining = append(remaining, arg)
+			continue
+		}
+
+		taken = append(taken, arg)
+		if consumesNext && i+1 < len(rawOptions) && looksLikeValue(rawOptions[i+1]) {
+			i++
+			taken = append(taken, rawOptions[i])
+		}
+	}
+	return remaining, taken
+}
+
+// globalOption checks if the raw option is a global option which is not shadowed by an
+// option of the command, and if its value is the next raw option
+func globalOption(
+	arg string,
+	globalIndex map[string]InputOptionDefinition,
+	shadowIndex map[string]InputOptionDefinition,
+) (consumesNext bool, isGlobal bool) {
+	var name string
+	var hasValue bool
+	switch {
+	case strings.HasPrefix(arg, "--"):
+		name, _, hasValue = strings.Cut(arg[2:], "=")
+		name = strings.TrimSpace(name)
+	case strings.HasPrefix(arg, "-") && len(arg) >= 2 && !isNumber(arg):
+		name = arg[1:2]
+		hasValue = len(arg) > 2
+	default:
+		return false, false
+	}
+
+	def, known := globalIndex[name]
+	if !known && !hasValue && strings.HasPrefix(arg, "--no-") {
+		def, known = globalIndex[strings.TrimPrefix(name, "no-")]
+		known, hasValue = known && def.isNegatable(), true
+	}
+	if !known || (len(name) == 1 && hasValue && def.IsFlag()) {
+		return false, false
+	}
+	if _, shadowed := shadowIndex[name]; shadowed {
+		return false, false
+	}
+	if _, shadowed := shadowIndex[def.name]; shadowed {
+		return false, false
+	}
+	return !hasValue && !def.IsFlag(), true
+}
+
+// buildGlobalOptions validates the global options taken from the command line. The options
+// which were not provided get their environment or default values.
+func buildGlobalOptions(
+	taken []string,
+	globals InputOptionDefinitionMap,
+) (InputOptionsMap, []error) {
+	parsed := parseArgs(taken, globals)
+	options, errs := buildOptions(parsed.options, globals, false)
+	return options, append(parsed.errs, errs...)
+}
+
+// withGlobalDefinitions merges the global option definitions with the command ones, except the
+// global options shadowed by a name or an alias of the command options
+func withGlobalDefinitions(
+	definitions InputOptionDefinitionMap,
+	globals InputOptionDefinitionMap,
+) InputOptionDefinitionMap {
+	index := definitions.index()
+	merged := maps.Clone(definitions)
+	if merged == nil {
+		merged = InputOptionDefinitionMap{}
+	}
+	for name, def := range globals {
+		shadowed := slices.ContainsFunc(
+			append([]string{def.name}, def.aliases...), func(name string) bool {
+				_, declared := index[name]
+				return declared
+			},
+		)
+		if !shadowed {
+			merged[name] = def
+		}
+	}
+	return merged
+}
+
+// withGlobalOptions adds the global options to the options of the command, except the ones
+// shadowed by an option declared by the command
+func withGlobalOptions(
+	options InputOptionsMap,
+	globalOptions InputOptionsMap,
+	definitions InputOptionDefinitionMap,
+) InputOptionsMap {
+	index := definitions.index()
+	for name, option := range globalOptions {
+		if _, shadowed := index[name]; shadowed {
+			continue
+		}
+		if options == nil {
+			options = InputOptionsMap{}
+		}
+		options[name] = option
+	}
+	return options
+}
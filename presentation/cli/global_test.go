@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/rsgcata/gocommon/params"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"maps"
+	"slices"
+	"testing"
+)
+
+type GlobalOptionsSuite struct {
+	suite.Suite
+}
+
+func TestGlobalOptionsSuite(t *testing.T) {
+	suite.Run(t, new(GlobalOptionsSuite))
+}
+
+func (s *GlobalOptionsSuite) globals() InputOptionDefinitionMap {
+	return MustInputOptionDefinitionMap(
+		NewOption("config").Alias("c").Description("Config file"),
+		NewOption("verbose").Alias("v").Flag(),
+		NewOption("log-level").Enum("debug", "info").Default("info"),
+	)
+}
+
+func (s *GlobalOptionsSuite) TestItTakesGlobalOptionsFromRawOptions() {
+	tests := []struct {
+		name          string
+		rawOptions    []string
+		shadowing     InputOptionDefinitionMap
+		leadingOnly   bool
+		wantRemaining []string
+		wantTaken     []string
+	}{
+		{
+			"Long options",
+			[]string{"--config=a.yml", "--name=x", "--verbose"},
+			nil,
+			false,
+			[]string{"--name=x"},
+			[]string{"--config=a.yml", "--verbose"},
+		},
+		{
+			"Separate values and aliases",
+			[]string{"--config", "a.yml", "x", "-c", "b.yml", "-v"},
+			nil,
+			false,
+			[]string{"x"},
+			[]string{"--config", "a.yml", "-c", "b.yml", "-v"},
+		},
+		{
+			"Negated flag",
+			[]string{"--no-verbose", "--no-config"},
+			nil,
+			false,
+			[]string{"--no-config"},
+			[]string{"--no-verbose"},
+		},
+		{
+			"Separator",
+			[]string{"--verbose", "--", "--config=a.yml"},
+			nil,
+			false,
+			[]string{"--", "--config=a.yml"},
+			[]string{"--verbose"},
+		},
+		{
+			"Shadowed by the command",
+			[]string{"--config=a.yml", "-v"},
+			MustInputOptionDefinitionMap(NewOption("config"), NewOption("version").Alias("v")),
+			false,
+			[]string{"--config=a.yml", "-v"},
+			nil,
+		},
+		{
+			"Grouped aliases",
+			[]string{"-vx"},
+			nil,
+			false,
+			[]string{"-vx"},
+			nil,
+		},
+		{
+			"Leading only",
+			[]string{"-v", "--config", "a.yml", "db", "--log-level=debug"},
+			nil,
+			true,
+			[]string{"db", "--log-level=debug"},
+			[]string{"-v", "--config", "a.yml"},
+		},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				remaining, taken := takeGlobalOptions(
+					scenario.rawOptions,
+					s.globals(),
+					scenario.shadowing,
+					scenario.leadingOnly,
+				)
+				s.Equal(scenario.wantRemaining, remaining)
+				s.Equal(scenario.wantTaken, taken)
+			},
+		)
+	}
+}
+
+func (s *GlobalOptionsSuite) bootstrap(
+	args []string,
+	cmd Command,
+) (stdout string, stderr string, exitCode int) {
+	registry := NewCommandsRegistry()
+	_ = registry.Register(cmd)
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	BootstrapWithOptions(
+		args,
+		*registry,
+		BootstrapOptions{
+			IO:            IO{Stdout: &stdoutBuf, Stderr: &stderrBuf},
+			ProcessExit:   func(code int) { exitCode = code },
+			GlobalOptions: s.globals(),
+		},
+	)
+	return stdoutBuf.String(), stderrBuf.String(), exitCode
+}
+
+func (s *GlobalOptionsSuite) TestBootstrapPassesGlobalOptionsToCommands() {
+	var gotOptions InputOptionsMap
+	cmd := &bootstrapMockCommand{
+		id:       "db migrate",
+		inputDef: MustInputOptionDefinitionMap(NewOption("steps").Type(OptionTypeInt)),
+		execFunc: func(options InputOptionsMap, _ io.Writer) error {
+			gotOptions = options
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{
+			"Before the command id",
+			[]string{"-v", "--config", "a.yml", "db", "migrate", "--steps=2"},
+		},
+		{"After the command id", []string{"db", "migrate", "--config=a.yml", "--steps=2", "-v"}},
+		{"Both", []string{"--verbose", "db", "migrate", "--steps", "2", "-c", "a.yml"}},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				gotOptions = nil
+				_, stderr, exitCode := s.bootstrap(scenario.args, cmd)
+
+				s.Equal(StatusOk, exitCode, stderr)
+				s.Equal(params.RawVal("2"), gotOptions["steps"].RawVal())
+				s.Equal(params.RawVal("a.yml"), gotOptions["config"].RawVal())
+				s.Equal(ValueSourceUser, gotOptions["config"].Source())
+				s.Equal(params.RawVal("true"), gotOptions["verbose"].RawVal())
+				s.Equal(params.RawVal("info"), gotOptions["log-level"].RawVal())
+				s.Equal(ValueSourceDefault, gotOptions["log-level"].Source())
+				s.Equal(params.RawVal("table"), gotOptions["output"].RawVal())
+			},
+		)
+	}
+}
+
+func (s *GlobalOptionsSuite) TestCommandOptionsShadowGlobalOptions() {
+	var gotOptions InputOptionsMap
+	cmd := &bootstrapMockCommand{
+		id:       "deploy",
+		inputDef: MustInputOptionDefinitionMap(NewOption("config").Type(OptionTypeInt)),
+		execFunc: func(options InputOptionsMap, _ io.Writer) error {
+			gotOptions = options
+			return nil
+		},
+	}
+
+	_, stderr, exitCode := s.bootstrap([]string{"deploy", "--config=3"}, cmd)
+
+	s.Equal(StatusOk, exitCode, stderr)
+	s.Equal(params.RawVal("3"), gotOptions["config"].RawVal())
+}
+
+func (s *GlobalOptionsSuite) TestItMergesUnshadowedGlobalDefinitions() {
+	commandDefinitions := MustInputOptionDefinitionMap(
+		NewOption("config").Type(OptionTypeInt),
+		NewOption("version").Alias("v").Flag(),
+	)
+
+	merged := withGlobalDefinitions(commandDefinitions, s.globals())
+
+	s.ElementsMatch([]string{"config", "version", "log-level"}, slices.Collect(maps.Keys(merged)))
+	s.Equal(OptionTypeInt, merged["config"].Type())
+	s.Len(withGlobalDefinitions(nil, s.globals()), 3)
+}
+
+func (s *GlobalOptionsSuite) TestBootstrapValidatesGlobalOptionsOnce() {
+	executed := false
+	cmd := &bootstrapMockCommand{
+		id:       "deploy",
+		inputDef: InputOptionDefinitionMap{},
+		execFunc: func(_ InputOptionsMap, _ io.Writer) error {
+			executed = true
+			return nil
+		},
+	}
+
+	_, stderr, exitCode := s.bootstrap(
+		[]string{"--log-level=trace", "deploy", "--verbose", "--verbose"},
+		cmd,
+	)
+
+	s.Equal(StatusUsage, exitCode)
+	s.False(executed)
+	s.Contains(stderr, "invalid global options")
+	s.Contains(stderr, "option 'log-level' expects one of debug, info, got 'trace'")
+	s.Contains(stderr, "option 'verbose' is defined twice")
+}
+
+func (s *GlobalOptionsSuite) TestHelpListsGlobalOptions() {
+	cmd := &bootstrapMockCommand{id: "deploy", inputDef: InputOptionDefinitionMap{}}
+
+	stdout, _, exitCode := s.bootstrap([]string{"-v", "help"}, cmd)
+	s.Equal(StatusOk, exitCode)
+	s.Contains(stdout, "Available CLI Commands:\n")
+	s.Contains(stdout, "Global options:\n")
+	s.Contains(stdout, "-c, --config Config file (default )\n")
+	s.Contains(stdout, "--output=<table|json|yaml|csv> The format of the command results")
+
+	stdout, _, exitCode = s.bootstrap([]string{"help", "--format=json"}, cmd)
+	s.Equal(StatusOk, exitCode)
+	var document helpDocument
+	s.Require().NoError(json.Unmarshal([]byte(stdout), &document))
+	var names []string
+	for _, option := range document.GlobalOptions {
+		names = append(names, option.Name)
+	}
+	s.Equal(
+		[]string{"config", "log-level", "no-color", "no-interaction", "output", "verbose", "yes"},
+		names,
+	)
+
+	stdout, _, exitCode = s.bootstrap([]string{"help", "--format=markdown"}, cmd)
+	s.Equal(StatusOk, exitCode)
+	s.Contains(stdout, "# CLI Commands\n\n**Global options:**\n\n| Option |")
+}
@@ -3,12 +3,30 @@ package cli
 import (
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strings"
 	"text/tabwriter"
 )
 
+// HelpCommand lists the available commands, grouped by their command group. When group is
+// set, only the commands in that group (and its subgroups) are listed. When a command id (or
+// alias) is passed as argument, the detailed help of that command is shown instead. The
+// global options are listed in the header. Headings are styled by the styler, if any.
 type HelpCommand struct {
 	availableCommands []Command
+	groups            map[string]string
+	aliases           map[string]string
+	globalOptions     InputOptionDefinitionMap
+	styler            *Styler
+	group             string
+}
+
+// ExamplesCommand is implemented by commands which document usage examples. Each example is
+// a full command line and is listed in the detailed help of the command.
+type ExamplesCommand interface {
+	Command
+	Examples() []string
 }
 
 func (c *HelpCommand) Id() string {
@@ -16,44 +34,378 @@ func (c *HelpCommand) Id() string {
 }
 
 func (c *HelpCommand) Description() string {
-	return "Lists all available commands"
+	return "Lists all available commands, or shows the detailed help of the given command"
 }
 
 func (c *HelpCommand) InputDefinition() InputOptionDefinitionMap {
-	return InputOptionDefinitionMap{}
+	return MustInputOptionDefinitionMap(
+		NewOption("format").
+			Description("The help output format").
+			Enum(helpFormatText, helpFormatJson, helpFormatMarkdown).
+			Default(helpFormatText),
+	)
+}
+
+func (c *HelpCommand) ArgumentsDefinition() InputArgumentDefinitionList {
+	return MustInputArgumentDefinitionList(
+		NewArgument("command").Description("The command or group to show the help for").Variadic(),
+	)
+}
+
+func (c *HelpCommand) ExecWithArguments(
+	arguments InputArgumentsMap,
+	options InputOptionsMap,
+	writer io.Writer,
+) error {
+	var words []string
+	for _, word := range arguments["command"].RawVals() {
+		words = append(words, string(word))
+	}
+	path := strings.Join(words, " ")
+	if path == "" {
+		return c.Exec(options, writer)
+	}
+
+	registry := c.registry()
+	if command, exists := registry.Command(path); exists {
+		switch string(options["format"].RawVal()) {
+		case helpFormatJson:
+			return writeHelpJson(
+				writer,
+				helpDocument{
+					GlobalOptions: newOptionDocs(c.globalOptions),
+					Groups:        []groupDoc{},
+					Commands:      []commandDoc{newCommandDoc(command)},
+				},
+			)
+		case helpFormatMarkdown:
+			writeCommandMarkdown(writer, command, "##")
+		default:
+			writeCommandDetails(writer, command, c.styler)
+		}
+		return nil
+	}
+
+	if _, isGroup := registry.Groups()[path]; isGroup {
+		groupHelpCmd := *c
+		groupHelpCmd.group = path
+		return groupHelpCmd.Exec(options, writer)
+	}
+
+	return &UsageError{unknownCommandError(path, registry)}
 }
 
-func (c *HelpCommand) Exec(_ InputOptionsMap, baseWriter io.Writer) error {
+// registry indexes the commands known by the help command, to look them up by id or alias
+func (c *HelpCommand) registry() CommandsRegistry {
+	registry := CommandsRegistry{
+		commands: map[string]Command{},
+		groups:   maps.Clone(c.groups),
+		aliases:  maps.Clone(c.aliases),
+	}
+	for _, command := range c.availableCommands {
+		registry.commands[strings.Join(strings.Fields(command.Id()), " ")] = command
+	}
+	registry.commands[c.Id()] = c
+	return registry
+}
+
+func (c *HelpCommand) Exec(options InputOptionsMap, baseWriter io.Writer) error {
+	groups, commandsByGroup := c.groupedCommands()
+	switch string(options["format"].RawVal()) {
+	case helpFormatJson:
+		return writeHelpJson(baseWriter, c.document(groups, commandsByGroup))
+	case helpFormatMarkdown:
+		c.writeMarkdown(baseWriter, groups, commandsByGroup)
+		return nil
+	}
+
 	writer := tabwriter.NewWriter(baseWriter, 0, 0, 1, ' ', 0)
-	_, _ = fmt.Fprintln(writer, c.Id()+"\tAvailable CLI Commands:")
+	if c.group == "" {
+		_, _ = fmt.Fprintln(writer, c.Id()+"\t"+c.styler.Heading("Available CLI Commands:"))
+	} else {
+		_, _ = fmt.Fprintln(
+			writer,
+			c.group+"\t"+c.styler.Heading("Available CLI Commands in group "+c.group+":"),
+		)
+		if description := c.groups[c.group]; description != "" {
+			_, _ = fmt.Fprintln(writer, "\t"+description)
+		}
+	}
+	if len(c.globalOptions) > 0 {
+		_, _ = fmt.Fprintln(writer, "\t"+c.styler.Heading("Global options:"))
+		writeOptionsHelp(writer, c.globalOptions)
+	}
+
+	for _, group := range groups {
+		if group != "" && group != c.group {
+			_, _ = fmt.Fprintln(writer, "=========\t")
+			_, _ = fmt.Fprintln(writer, group+"\t"+c.groups[group])
+		}
+
+		for _, command := range commandsByGroup[group] {
+			writeCommandHelp(writer, command, c.styler)
+		}
+	}
+	_ = writer.Flush()
 
+	return nil
+}
+
+// groupedCommands returns the sorted groups to be listed, with their commands sorted by id.
+// When the help is limited to a group, only that group and its subgroups are returned.
+func (c *HelpCommand) groupedCommands() (groups []string, commandsByGroup map[string][]Command) {
+	commandsByGroup = map[string][]Command{}
 	for _, command := range c.availableCommands {
-		_, _ = fmt.Fprintln(writer, "_________\t")
+		if isHidden(command) {
+			continue
+		}
+		group := commandGroup(strings.Join(strings.Fields(command.Id()), " "))
+		commandsByGroup[group] = append(commandsByGroup[group], command)
+	}
 
-		descChunks := chunkDescription(command.Description(), 80)
-		_, _ = fmt.Fprintln(writer, command.Id()+"\t"+descChunks[0])
-		if len(descChunks) > 1 {
-			for _, descChunk := range descChunks[1:] {
-				_, _ = fmt.Fprintln(writer, "\t"+descChunk)
-			}
+	allGroups := slices.Collect(maps.Keys(c.groups))
+	allGroups = append(allGroups, slices.Collect(maps.Keys(commandsByGroup))...)
+	slices.Sort(allGroups)
+	for _, group := range slices.Compact(allGroups) {
+		if c.group != "" && group != c.group && !strings.HasPrefix(group, c.group+" ") {
+			continue
+		}
+
+		slices.SortFunc(
+			commandsByGroup[group], func(a, b Command) int {
+				return strings.Compare(a.Id(), b.Id())
+			},
+		)
+		groups = append(groups, group)
+	}
+	return groups, commandsByGroup
+}
+
+// writeCommandHelp writes the summary of the command. Only the text after the last tab of a
+// line is styled, as the escape sequences would break the alignment of the columns.
+func writeCommandHelp(writer io.Writer, command Command, styler *Styler) {
+	_, _ = fmt.Fprintln(writer, "_________\t")
+
+	descChunks := chunkDescription(command.Description(), 80)
+	_, _ = fmt.Fprintln(writer, command.Id()+"\t"+descChunks[0])
+	if len(descChunks) > 1 {
+		for _, descChunk := range descChunks[1:] {
+			_, _ = fmt.Fprintln(writer, "\t"+descChunk)
+		}
+	}
+
+	if aliasedCmd, ok := command.(AliasedCommand); ok && len(aliasedCmd.Aliases()) > 0 {
+		_, _ = fmt.Fprintln(
+			writer,
+			"\t"+styler.Heading("Aliases:")+" "+strings.Join(aliasedCmd.Aliases(), ", "),
+		)
+	}
+
+	_, _ = fmt.Fprintln(writer, "\t"+styler.Heading("Usage:")+" "+commandUsage(command))
+
+	if argumentDefs := argumentsDefinitionOf(command); len(argumentDefs) > 0 {
+		_, _ = fmt.Fprintln(writer, "\t"+styler.Heading("Arguments:"))
+		for _, def := range argumentDefs {
+			_, _ = fmt.Fprintf(writer, "\t%s %s\n", def.usage(), def.description)
+		}
+	}
+
+	if len(command.InputDefinition()) > 0 {
+		_, _ = fmt.Fprintln(writer, "\t"+styler.Heading("Options:"))
+		writeOptionsHelp(writer, command.InputDefinition())
+	}
+
+	if exitCodes := exitCodesOf(command); len(exitCodes) > 0 {
+		_, _ = fmt.Fprintln(writer, "\t"+styler.Heading("Exit codes:"))
+		for _, code := range slices.Sorted(maps.Keys(exitCodes)) {
+			_, _ = fmt.Fprintf(writer, "\t%d %s\n", code, exitCodes[code])
+		}
+	}
+}
+
+// writeOptionsHelp lists the options, sorted, one per line
+func writeOptionsHelp(writer io.Writer, definitions InputOptionDefinitionMap) {
+	for _, def := range sortedOptionDefinitions(definitions) {
+		_, _ = fmt.Fprintf(
+			writer,
+			"\t%s %s (default %s)%s\n",
+			optionLabel(def),
+			def.description,
+			def.defaultVal,
+			optionEnvLabel(def),
+		)
+	}
+}
+
+// writeCommandDetails writes the detailed help page of the command: usage line, full
+// description, aliases, arguments, options with their types, defaults and required markers,
+// exit codes and examples
+func writeCommandDetails(baseWriter io.Writer, command Command, styler *Styler) {
+	writer := tabwriter.NewWriter(baseWriter, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(writer, "%s\n  %s\n", styler.Heading("Usage:"), commandUsage(command))
+
+	if command.Description() != "" {
+		_, _ = fmt.Fprintln(writer, "\n"+styler.Heading("Description:"))
+		for _, line := range strings.Split(command.Description(), "\n") {
+			_, _ = fmt.Fprintln(writer, "  "+line)
 		}
+	}
+
+	if aliasedCmd, ok := command.(AliasedCommand); ok && len(aliasedCmd.Aliases()) > 0 {
+		_, _ = fmt.Fprintf(
+			writer,
+			"\n%s\n  %s\n",
+			styler.Heading("Aliases:"),
+			strings.Join(aliasedCmd.Aliases(), ", "),
+		)
+	}
 
-		if len(command.InputDefinition()) > 0 {
-			_, _ = fmt.Fprintln(writer, "\tOptions:")
-			for _, def := range command.InputDefinition() {
-				_, _ = fmt.Fprintf(
-					writer,
-					"\t--%s %s (default %s)\n",
-					def.name,
-					def.description,
-					def.defaultVal,
-				)
+	if argumentDefs := argumentsDefinitionOf(command); len(argumentDefs) > 0 {
+		_, _ = fmt.Fprintln(writer, "\n"+styler.Heading("Arguments:"))
+		for _, def := range argumentDefs {
+			details := ""
+			if def.required {
+				details = " [required]"
 			}
+			_, _ = fmt.Fprintf(writer, "  %s\t%s%s\n", def.usage(), def.description, details)
+		}
+	}
+
+	if len(command.InputDefinition()) > 0 {
+		_, _ = fmt.Fprintln(writer, "\n"+styler.Heading("Options:"))
+		for _, def := range sortedOptionDefinitions(command.InputDefinition()) {
+			_, _ = fmt.Fprintf(
+				writer,
+				"  %s\t%s [%s]\n",
+				optionLabel(def),
+				def.description,
+				strings.Join(optionDetails(def), ", "),
+			)
+		}
+	}
+
+	if exitCodes := exitCodesOf(command); len(exitCodes) > 0 {
+		_, _ = fmt.Fprintln(writer, "\n"+styler.Heading("Exit codes:"))
+		for _, code := range slices.Sorted(maps.Keys(exitCodes)) {
+			_, _ = fmt.Fprintf(writer, "  %d\t%s\n", code, exitCodes[code])
+		}
+	}
+
+	if examplesCmd, ok := command.(ExamplesCommand); ok && len(examplesCmd.Examples()) > 0 {
+		_, _ = fmt.Fprintln(writer, "\n"+styler.Heading("Examples:"))
+		for _, example := range examplesCmd.Examples() {
+			_, _ = fmt.Fprintln(writer, "  "+example)
 		}
 	}
 	_ = writer.Flush()
+}
 
-	return nil
+// optionDetails lists the option type, default value and required marker, like
+// ["type: int", "default: 8080"]
+func optionDetails(def InputOptionDefinition) []string {
+	details := []string{"type: " + def.valueType.String()}
+	if def.list {
+		details = append(details, "comma separated list")
+	} else if def.repeatable {
+		details = append(details, "repeatable")
+	}
+	if def.envVar != "" {
+		details = append(details, "env: "+def.envVar)
+	}
+	if def.defaultVal != "" {
+		details = append(details, "default: "+def.defaultVal)
+	}
+	if def.required {
+		details = append(details, "required")
+	}
+	return details
+}
+
+// optionEnvLabel returns the environment variable label of the option, like " (env PORT)"
+func optionEnvLabel(def InputOptionDefinition) string {
+	if def.envVar == "" {
+		return ""
+	}
+	return " (env " + def.envVar + ")"
+}
+
+// sortedOptionDefinitions returns the option definitions sorted by name, so help output is
+// stable between runs
+func sortedOptionDefinitions(definitions InputOptionDefinitionMap) []InputOptionDefinition {
+	return slices.SortedFunc(
+		maps.Values(definitions), func(a, b InputOptionDefinition) int {
+			return strings.Compare(a.name, b.name)
+		},
+	)
+}
+
+// helpRequested checks if the --help or -h flag is present in the command input. The flags
+// are ignored when the command declares options with the same names.
+func helpRequested(rawOptions []string, definitions InputOptionDefinitionMap) bool {
+	index := definitions.index()
+	for _, option := range parseArgs(rawOptions, definitions).options {
+		if _, declared := index[option.name]; declared || option.value == "false" {
+			continue
+		}
+		if option.name == "help" || option.name == "h" {
+			return true
+		}
+	}
+	return false
+}
+
+// commandUsage builds the command usage line, like "import [options] <file> [<files>...]"
+func commandUsage(command Command) string {
+	usage := command.Id()
+	if len(command.InputDefinition()) > 0 {
+		usage += " [options]"
+	}
+	if argumentDefs := argumentsDefinitionOf(command); len(argumentDefs) > 0 {
+		usage += " " + argumentDefs.usage()
+	}
+	return usage
+}
+
+// optionLabel builds the option usage label, like "-p, --port=<int>". Repeatable options end
+// with "...".
+func optionLabel(def InputOptionDefinition) string {
+	var names []string
+	for _, alias := range def.aliases {
+		if len(alias) == 1 {
+			names = append(names, optionFlagName(alias))
+		}
+	}
+	names = append(names, optionFlagName(def.name))
+	for _, alias := range def.aliases {
+		if len(alias) > 1 {
+			names = append(names, optionFlagName(alias))
+		}
+	}
+
+	if def.isNegatable() {
+		names = append(names, "--no-"+def.name)
+	}
+	if def.IsFlag() {
+		return strings.Join(names, ", ")
+	}
+
+	label := strings.Join(names, ", ")
+	if def.valueType != OptionTypeString {
+		label += "=" + def.valuePlaceholder()
+	}
+	if def.IsRepeatable() {
+		label += "..."
+	}
+	return label
+}
+
+// optionFlagName returns the name as typed on the command line, "-v" for single character
+// names and "--name" for the others
+func optionFlagName(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
 }
 
 func chunkDescription(description string, size int) []string {
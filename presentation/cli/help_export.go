@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Output formats accepted by the help command --format option
+const (
+	helpFormatText     = "text"
+	helpFormatJson     = "json"
+	helpFormatMarkdown = "markdown"
+)
+
+// helpDocument is the machine-readable help, serialized by "help --format=json"
+type helpDocument struct {
+	GlobalOptions []optionDoc  `json:"globalOptions"`
+	Groups        []groupDoc   `json:"groups"`
+	Commands      []commandDoc `json:"commands"`
+}
+
+type groupDoc struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type commandDoc struct {
+	Id          string        `json:"id"`
+	Description string        `json:"description"`
+	Group       string        `json:"group"`
+	Usage       string        `json:"usage"`
+	Aliases     []string      `json:"aliases"`
+	Arguments   []argumentDoc `json:"arguments"`
+	Options     []optionDoc   `json:"options"`
+	ExitCodes   []exitCodeDoc `json:"exitCodes"`
+	Examples    []string      `json:"examples"`
+}
+
+type argumentDoc struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+	Variadic    bool   `json:"variadic"`
+}
+
+type optionDoc struct {
+	Name        string   `json:"name"`
+	Aliases     []string `json:"aliases"`
+	Description string   `json:"description"`
+	Type        string   `json:"type"`
+	EnumValues  []string `json:"enumValues,omitempty"`
+	EnvVar      string   `json:"envVar"`
+	Default     string   `json:"default"`
+	Required    bool     `json:"required"`
+	Repeatable  bool     `json:"repeatable"`
+	List        bool     `json:"list"`
+}
+
+type exitCodeDoc struct {
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+}
+
+// document builds the machine-readable help of the provided groups and commands
+func (c *HelpCommand) document(
+	groups []string,
+	commandsByGroup map[string][]Command,
+) helpDocument {
+	document := helpDocument{
+		GlobalOptions: newOptionDocs(c.globalOptions),
+		Groups:        []groupDoc{},
+		Commands:      []commandDoc{},
+	}
+	for _, group := range groups {
+		if group != "" {
+			document.Groups = append(
+				document.Groups,
+				groupDoc{Name: group, Description: c.groups[group]},
+			)
+		}
+		for _, command := range commandsByGroup[group] {
+			document.Commands = append(document.Commands, newCommandDoc(command))
+		}
+	}
+	return document
+}
+
+func newCommandDoc(command Command) commandDoc {
+	path := strings.Join(strings.Fields(command.Id()), " ")
+	doc := commandDoc{
+		Id:          path,
+		Description: command.Description(),
+		Group:       commandGroup(path),
+		Usage:       commandUsage(command),
+		Aliases:     []string{},
+		Arguments:   []argumentDoc{},
+		Options:     newOptionDocs(command.InputDefinition()),
+		ExitCodes:   []exitCodeDoc{},
+		Examples:    []string{},
+	}
+
+	if aliasedCmd, ok := command.(AliasedCommand); ok {
+		doc.Aliases = append(doc.Aliases, aliasedCmd.Aliases()...)
+	}
+	for _, def := range argumentsDefinitionOf(command) {
+		doc.Arguments = append(
+			doc.Arguments,
+			argumentDoc{
+				Name:        def.name,
+				Description: def.description,
+				Required:    def.required,
+				Variadic:    def.variadic,
+			},
+		)
+	}
+	exitCodes := exitCodesOf(command)
+	for _, code := range slices.Sorted(maps.Keys(exitCodes)) {
+		doc.ExitCodes = append(doc.ExitCodes, exitCodeDoc{Code: code, Description: exitCodes[code]})
+	}
+	if examplesCmd, ok := command.(ExamplesCommand); ok {
+		doc.Examples = append(doc.Examples, examplesCmd.Examples()...)
+	}
+	return doc
+}
+
+// newOptionDocs builds the machine-readable help of the options, sorted by name
+func newOptionDocs(definitions InputOptionDefinitionMap) []optionDoc {
+	docs := []optionDoc{}
+	for _, def := range sortedOptionDefinitions(definitions) {
+		docs = append(
+			docs,
+			optionDoc{
+				Name:        def.name,
+				Aliases:     append([]string{}, def.aliases...),
+				Description: def.description,
+				Type:        def.valueType.String(),
+				EnumValues:  def.EnumValues(),
+				EnvVar:      def.envVar,
+				Default:     def.defaultVal,
+				Required:    def.required,
+				Repeatable:  def.IsRepeatable(),
+				List:        def.list,
+			},
+		)
+	}
+	return docs
+}
+
+func writeHelpJson(writer io.Writer, document helpDocument) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("failed to encode the help as json: %w", err)
+	}
+	return nil
+}
+
+// writeMarkdown writes the help of the provided groups and commands as a Markdown reference.
+// Groups and ungrouped commands are level 2 headings, grouped commands are level 3 headings.
+func (c *HelpCommand) writeMarkdown(
+	writer io.Writer,
+	groups []string,
+	commandsByGroup map[string][]Command,
+) {
+	_, _ = fmt.Fprint(writer, "# CLI Commands\n")
+	if len(c.globalOptions) > 0 {
+		_, _ = fmt.Fprint(writer, "\n**Global options:**\n\n")
+		writeOptionsMarkdown(writer, c.globalOptions)
+	}
+	for _, group := range groups {
+		commandHeading := "##"
+		if group != "" {
+			commandHeading = "###"
+			_, _ = fmt.Fprintf(writer, "\n## `%s`\n", group)
+			if description := c.groups[group]; description != "" {
+				_, _ = fmt.Fprintf(writer, "\n%s\n", description)
+			}
+		}
+
+		for _, command := range commandsByGroup[group] {
+			_, _ = fmt.Fprintln(writer)
+			writeCommandMarkdown(writer, command, commandHeading)
+		}
+	}
+}
+
+// writeCommandMarkdown writes the detailed help of the command as Markdown, under a heading
+// of the provided level (like "##")
+func writeCommandMarkdown(writer io.Writer, command Command, heading string) {
+	doc := newCommandDoc(command)
+	_, _ = fmt.Fprintf(writer, "%s `%s`\n", heading, doc.Id)
+	if doc.Description != "" {
+		_, _ = fmt.Fprintf(writer, "\n%s\n", doc.Description)
+	}
+	_, _ = fmt.Fprintf(writer, "\n**Usage:** `%s`\n", doc.Usage)
+
+	if len(doc.Aliases) > 0 {
+		_, _ = fmt.Fprintf(writer, "\n**Aliases:** `%s`\n", strings.Join(doc.Aliases, "`, `"))
+	}
+
+	if len(doc.Arguments) > 0 {
+		_, _ = fmt.Fprint(writer, "\n**Arguments:**\n\n")
+		_, _ = fmt.Fprint(writer, "| Argument | Description | Required |\n| --- | --- | --- |\n")
+		for _, def := range argumentsDefinitionOf(command) {
+			_, _ = fmt.Fprintf(
+				writer,
+				"| `%s` | %s | %s |\n",
+				def.usage(),
+				markdownCell(def.description),
+				markdownBool(def.required),
+			)
+		}
+	}
+
+	if len(doc.Options) > 0 {
+		_, _ = fmt.Fprint(writer, "\n**Options:**\n\n")
+		writeOptionsMarkdown(writer, command.InputDefinition())
+	}
+
+	if len(doc.ExitCodes) > 0 {
+		_, _ = fmt.Fprint(writer, "\n**Exit codes:**\n\n| Code | Description |\n| --- | --- |\n")
+		for _, exitCode := range doc.ExitCodes {
+			_, _ = fmt.Fprintf(
+				writer,
+				"| %d | %s |\n",
+				exitCode.Code,
+				markdownCell(exitCode.Description),
+			)
+		}
+	}
+
+	if len(doc.Examples) > 0 {
+		_, _ = fmt.Fprintf(
+			writer,
+			"\n**Examples:**\n\n```sh\n%s\n```\n",
+			strings.Join(doc.Examples, "\n"),
+		)
+	}
+}
+
+// writeOptionsMarkdown writes the options, sorted, as a Markdown table
+func writeOptionsMarkdown(writer io.Writer, definitions InputOptionDefinitionMap) {
+	_, _ = fmt.Fprint(
+		writer,
+		"| Option | Description | Type | Env | Default | Required |\n"+
+			"| --- | --- | --- | --- | --- | --- |\n",
+	)
+	for _, def := range sortedOptionDefinitions(definitions) {
+		_, _ = fmt.Fprintf(
+			writer,
+			"| %s | %s | %s | %s | %s | %s |\n",
+			markdownCell("`"+optionLabel(def)+"`"),
+			markdownCell(def.description),
+			def.valueType,
+			def.envVar,
+			markdownCell(def.defaultVal),
+			markdownBool(def.required),
+		)
+	}
+}
+
+// markdownCell escapes the text so it can be used in a Markdown table cell
+func markdownCell(text string) string {
+	return strings.NewReplacer("|", "\\|", "\n", "<br>").Replace(text)
+}
+
+func markdownBool(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type HelpExportSuite struct {
+	suite.Suite
+}
+
+func TestHelpExportSuite(t *testing.T) {
+	suite.Run(t, new(HelpExportSuite))
+}
+
+func (s *HelpExportSuite) createRegistry() *CommandsRegistry {
+	registry := NewCommandsRegistry()
+	_ = registry.Register(
+		&aliasedMockCommand{
+			bootstrapMockCommand: bootstrapMockCommand{
+				id:          "db migrate",
+				description: "Runs the | pending migrations",
+				inputDef: MustInputOptionDefinitionMap(
+					NewOption("steps").Description("Steps to run").Type(OptionTypeInt).
+						Env("MIGRATE_STEPS").Default("1"),
+					NewOption("env").Alias("e").Enum("dev", "prod").Required(),
+				),
+			},
+			aliases: []string{"migrate"},
+		},
+	)
+	_ = registry.Register(&bootstrapMockCommand{id: "version", description: "Shows the version"})
+	_ = registry.RegisterGroup("db", "Database commands")
+	return registry
+}
+
+func (s *HelpExportSuite) bootstrap(args ...string) (stdout string, exitCode int) {
+	var buf bytes.Buffer
+	BootstrapWithOptions(
+		args,
+		*s.createRegistry(),
+		BootstrapOptions{
+			IO:          IO{Stdout: &buf, Stderr: &bytes.Buffer{}},
+			ProcessExit: func(code int) { exitCode = code },
+		},
+	)
+	return buf.String(), exitCode
+}
+
+func (s *HelpExportSuite) TestHelpCanBeExportedAsJson() {
+	output, exitCode := s.bootstrap("help", "--format=json")
+	s.Equal(StatusOk, exitCode)
+
+	var document helpDocument
+	s.NoError(json.Unmarshal([]byte(output), &document))
+	s.Equal([]groupDoc{{Name: "db", Description: "Database commands"}}, document.Groups)
+	s.Len(document.Commands, 3)
+
+	s.Equal("completion", document.Commands[0].Id)
+	s.Equal("version", document.Commands[1].Id)
+	s.Equal("", document.Commands[1].Group)
+	migrate := document.Commands[2]
+	s.Equal("db migrate", migrate.Id)
+	s.Equal("db", migrate.Group)
+	s.Equal("db migrate [options]", migrate.Usage)
+	s.Equal([]string{"migrate"}, migrate.Aliases)
+	s.Equal(
+		[]optionDoc{
+			{
+				Name:       "env",
+				Aliases:    []string{"e"},
+				Type:       "enum",
+				EnumValues: []string{"dev", "prod"},
+				Required:   true,
+			},
+			{
+				Name:        "steps",
+				Aliases:     []string{},
+				Description: "Steps to run",
+				Type:        "int",
+				EnvVar:      "MIGRATE_STEPS",
+				Default:     "1",
+			},
+		},
+		migrate.Options,
+	)
+	s.Contains(output, `"arguments": []`)
+
+	output, exitCode = s.bootstrap("help", "migrate", "--format", "json")
+	s.Equal(StatusOk, exitCode)
+	s.NoError(json.Unmarshal([]byte(output), &document))
+	s.Empty(document.Groups)
+	s.Len(document.Commands, 1)
+	s.Equal("db migrate", document.Commands[0].Id)
+
+	_, exitCode = s.bootstrap("help", "--format=xml")
+	s.Equal(StatusUsage, exitCode)
+}
+
+func (s *HelpExportSuite) TestHelpCanBeExportedAsMarkdown() {
+	output, exitCode := s.bootstrap("help", "--format=markdown")
+	s.Equal(StatusOk, exitCode)
+
+	s.Contains(output, "# CLI Commands\n")
+	s.Contains(output, "\n## `version`\n\nShows the version\n\n**Usage:** `version`\n")
+	s.Contains(output, "\n## `db`\n\nDatabase commands\n")
+	s.Contains(output, "\n### `db migrate`\n\nRuns the | pending migrations\n")
+	s.Contains(output, "**Aliases:** `migrate`\n")
+	s.Contains(output, "| `-e, --env=<dev\\|prod>` |  | enum |  |  | yes |\n")
+	s.Contains(output, "| `--steps=<int>` | Steps to run | int | MIGRATE_STEPS | 1 | no |\n")
+	s.Less(strings.Index(output, "`version`"), strings.Index(output, "`db`"))
+}
@@ -2,8 +2,10 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"github.com/stretchr/testify/suite"
 	"io"
+	"strings"
 	"testing"
 )
 
@@ -147,6 +149,74 @@ func (s *HelpSuite) TestHelpCommandExecutionCanShowAvailableCommandsInfo() {
 				"--option1", "First option", "default1",
 			},
 		},
+		{
+			name: "Command with typed options",
+			commands: []Command{
+				&mockCommand{
+					id:          "test",
+					description: "Test command",
+					inputDef: MustInputOptionDefinitionMap(
+						NewOption("port").Type(OptionTypeInt),
+						NewOption("mode").Enum("fast", "slow"),
+					),
+				},
+			},
+			contentChecks: []string{"--port=<int>", "--mode=<fast|slow>"},
+		},
+		{
+			name: "Command with option aliases",
+			commands: []Command{
+				&mockCommand{
+					id:          "test",
+					description: "Test command",
+					inputDef: MustInputOptionDefinitionMap(
+						NewOption("port").Alias("p", "listen").Type(OptionTypeInt),
+					),
+				},
+			},
+			contentChecks: []string{"-p, --port, --listen=<int>"},
+		},
+		{
+			name: "Command with flags",
+			commands: []Command{
+				&mockCommand{
+					id:          "test",
+					description: "Test command",
+					inputDef: MustInputOptionDefinitionMap(
+						NewOption("force").Alias("f").Flag(),
+						NewOption("no-cache").Flag(),
+					),
+				},
+			},
+			contentChecks: []string{"-f, --force, --no-force ", "  --no-cache "},
+		},
+		{
+			name: "Command with repeatable options",
+			commands: []Command{
+				&mockCommand{
+					id:          "test",
+					description: "Test command",
+					inputDef: MustInputOptionDefinitionMap(
+						NewOption("tag").Repeatable(),
+						NewOption("ids").Type(OptionTypeInt).List(),
+					),
+				},
+			},
+			contentChecks: []string{"--tag... ", "--ids=<int>... "},
+		},
+		{
+			name: "Command with options bound to environment variables",
+			commands: []Command{
+				&mockCommand{
+					id:          "test",
+					description: "Test command",
+					inputDef: MustInputOptionDefinitionMap(
+						NewOption("port").Description("Port").Env("APP_PORT").Default("80"),
+					),
+				},
+			},
+			contentChecks: []string{"--port Port (default 80) (env APP_PORT)"},
+		},
 	}
 
 	for _, scenario := range tests {
@@ -168,3 +238,200 @@ func (s *HelpSuite) TestHelpCommandExecutionCanShowAvailableCommandsInfo() {
 		)
 	}
 }
+
+// Mock command documenting usage examples
+type examplesMockCommand struct {
+	mockCommand
+	examples []string
+}
+
+func (m *examplesMockCommand) Examples() []string {
+	return m.examples
+}
+
+func (s *HelpSuite) TestHelpCommandCanShowCommandDetails() {
+	serve := &examplesMockCommand{
+		mockCommand: mockCommand{
+			id:          "serve",
+			description: "Starts the server.\nStops on SIGTERM.",
+			inputDef: MustInputOptionDefinitionMap(
+				NewOption("port").Description("Port to listen on").Type(OptionTypeInt).
+					Default("8080"),
+				NewOption("host").Description("Host name").Env("APP_HOST").Required(),
+			),
+		},
+		examples: []string{"app serve --port=9090 --host=localhost"},
+	}
+	cmd := &HelpCommand{
+		availableCommands: []Command{serve, &mockCommand{id: "db migrate"}},
+		aliases:           map[string]string{"start": "serve"},
+	}
+
+	tests := []struct {
+		name          string
+		arguments     []string
+		contentChecks []string
+	}{
+		{
+			name:      "Command id",
+			arguments: []string{"serve"},
+			contentChecks: []string{
+				"Usage:\n  serve [options]\n",
+				"Description:\n  Starts the server.\n  Stops on SIGTERM.\n",
+				"--host        Host name [type: string, env: APP_HOST, required]\n",
+				"--port=<int>  Port to listen on [type: int, default: 8080]\n",
+				"Examples:\n  app serve --port=9090 --host=localhost\n",
+			},
+		},
+		{
+			name:          "Command alias",
+			arguments:     []string{"start"},
+			contentChecks: []string{"Usage:\n  serve [options]\n"},
+		},
+		{
+			name:          "Nested command id",
+			arguments:     []string{"db", "migrate"},
+			contentChecks: []string{"Usage:\n  db migrate\n"},
+		},
+		{
+			name:          "Group",
+			arguments:     []string{"db"},
+			contentChecks: []string{"Available CLI Commands in group db:", "db migrate"},
+		},
+		{
+			name:          "Help itself",
+			arguments:     []string{"help"},
+			contentChecks: []string{"Usage:\n  help [options] [<command>...]\n"},
+		},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				var buf bytes.Buffer
+				err := cmd.ExecWithArguments(
+					InputArgumentsMap{"command": {rawVals: scenario.arguments}},
+					InputOptionsMap{},
+					&buf,
+				)
+				s.NoError(err)
+				for _, check := range scenario.contentChecks {
+					s.Contains(buf.String(), check)
+				}
+			},
+		)
+	}
+
+	var buf bytes.Buffer
+	err := cmd.ExecWithArguments(
+		InputArgumentsMap{"command": {rawVals: []string{"serv"}}},
+		InputOptionsMap{},
+		&buf,
+	)
+	var usageErr *UsageError
+	s.ErrorAs(err, &usageErr)
+	s.Contains(err.Error(), "The command serv does not exist")
+	s.Contains(err.Error(), "serve")
+}
+
+func (s *HelpSuite) TestHelpListsOptionsSortedByName() {
+	cmd := &HelpCommand{
+		availableCommands: []Command{
+			&mockCommand{
+				id: "test",
+				inputDef: MustInputOptionDefinitionMap(
+					NewOption("zeta"),
+					NewOption("alpha"),
+					NewOption("mid"),
+				),
+			},
+		},
+	}
+
+	for range 5 {
+		var buf bytes.Buffer
+		s.NoError(cmd.Exec(InputOptionsMap{}, &buf))
+		output := buf.String()
+		s.Less(strings.Index(output, "--alpha"), strings.Index(output, "--mid"))
+		s.Less(strings.Index(output, "--mid"), strings.Index(output, "--zeta"))
+	}
+}
+
+func (s *HelpSuite) TestBootstrapShowsCommandDetailsForHelpFlags() {
+	registry := NewCommandsRegistry()
+	executed := false
+	_ = registry.Register(
+		&bootstrapMockCommand{
+			id:          "db migrate",
+			description: "Runs the migrations",
+			inputDef:    MustInputOptionDefinitionMap(NewOption("steps").Type(OptionTypeInt)),
+			execFunc: func(_ InputOptionsMap, _ io.Writer) error {
+				executed = true
+				return nil
+			},
+		},
+	)
+	_ = registry.Register(
+		&bootstrapMockCommand{
+			id:       "search",
+			inputDef: MustInputOptionDefinitionMap(NewOption("host").Alias("h")),
+			execFunc: func(_ InputOptionsMap, _ io.Writer) error {
+				executed = true
+				return nil
+			},
+		},
+	)
+	_ = registry.Register(
+		&contextMockCommand{
+			bootstrapMockCommand: bootstrapMockCommand{
+				id:       "grep",
+				inputDef: InputOptionDefinitionMap{},
+			},
+			argumentsDef: MustInputArgumentDefinitionList(NewArgument("pattern")),
+			execContextFunc: func(_ context.Context, _ Invocation) error {
+				executed = true
+				return nil
+			},
+		},
+	)
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantExecuted bool
+		wantOutput   string
+	}{
+		{"Long flag", []string{"db", "migrate", "--help"}, false, "Usage:\n  db migrate"},
+		{"Short flag", []string{"db", "migrate", "--steps=1", "-h"}, false, "Runs the migrations"},
+		{"Help command", []string{"help", "db", "migrate"}, false, "Usage:\n  db migrate"},
+		{"Group flag", []string{"db", "--help"}, false, "Available CLI Commands in group db"},
+		{"Declared short option", []string{"search", "-h", "localhost"}, true, ""},
+		{"After separator", []string{"grep", "--", "--help"}, true, ""},
+		{"Application long flag", []string{"--help"}, false, "Available CLI Commands"},
+		{"Application short flag", []string{"-h"}, false, "Available CLI Commands"},
+		{"Application flag after globals", []string{"--no-color", "-h"}, false, "db migrate"},
+		{"Application flag with command", []string{"-h", "db", "migrate"}, false, "Usage:\n  db"},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				executed = false
+				var exitCode int
+				var stdout bytes.Buffer
+				BootstrapWithOptions(
+					scenario.args,
+					*registry,
+					BootstrapOptions{
+						IO:          IO{Stdout: &stdout, Stderr: &bytes.Buffer{}},
+						ProcessExit: func(code int) { exitCode = code },
+					},
+				)
+
+				s.Equal(StatusOk, exitCode)
+				s.Equal(scenario.wantExecuted, executed)
+				s.Contains(stdout.String(), scenario.wantOutput)
+			},
+		)
+	}
+}
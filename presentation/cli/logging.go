@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const CommandStartLogMessage = "CLI Command Started"
+const CommandFinishLogMessage = "CLI Command Finished"
+
+// redactedValue replaces the values of sensitive options in logs
+const redactedValue = "[REDACTED]"
+
+// logCommandStart logs the command id and the options provided on the command line. Values of
+// sensitive and undeclared options are redacted, an undeclared option may be a mistyped
+// sensitive one.
+func logCommandStart(
+	logger *slog.Logger,
+	cmdId string,
+	definitions InputOptionDefinitionMap,
+	rawOptions []string,
+) {
+	var optionAttrs []any
+	for _, option := range parseArgs(rawOptions, definitions).options {
+		value := option.value
+		if def, declared := definitions[option.name]; !declared || def.sensitive {
+			value = redactedValue
+		}
+		optionAttrs = append(optionAttrs, slog.String(option.name, value))
+	}
+
+	logger.LogAttrs(
+		context.Background(),
+		slog.LevelInfo,
+		CommandStartLogMessage,
+		slog.String("Command", cmdId),
+		slog.Group("Options", optionAttrs...),
+	)
+}
+
+// logCommandFinish logs the command result, at error level when the command failed, with the
+// bare error of the command. The stack trace is logged for panics.
+func logCommandFinish(
+	logger *slog.Logger,
+	cmdId string,
+	duration time.Duration,
+	exitCode int,
+	cmdErr error,
+) {
+	entries := []slog.Attr{
+		slog.String("Command", cmdId),
+		slog.String("Duration (s)", fmt.Sprintf("%.2f", duration.Seconds())),
+		slog.Int("Exit Status", exitCode),
+	}
+
+	level := slog.LevelInfo
+	if cmdErr != nil {
+		level = slog.LevelError
+		entries = append(entries, slog.String("Error", bareErrorMessage(cmdErr)))
+
+		var panicErr *PanicError
+		if errors.As(cmdErr, &panicErr) {
+			entries = append(entries, slog.String("Stack", string(panicErr.Stack)))
+		}
+	}
+
+	logger.LogAttrs(context.Background(), level, CommandFinishLogMessage, entries...)
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type LoggingSuite struct {
+	suite.Suite
+}
+
+func TestLoggingSuite(t *testing.T) {
+	suite.Run(t, new(LoggingSuite))
+}
+
+type commandLog struct {
+	Level    string            `json:"level"`
+	Msg      string            `json:"msg"`
+	Command  string            `json:"Command"`
+	Options  map[string]string `json:"Options"`
+	Duration string            `json:"Duration (s)"`
+	Status   *int              `json:"Exit Status"`
+	Error    string            `json:"Error"`
+}
+
+func (s *LoggingSuite) bootstrap(args []string, execErr error) []commandLog {
+	registry := NewCommandsRegistry()
+	_ = registry.Register(
+		&bootstrapMockCommand{
+			id: "connect",
+			inputDef: MustInputOptionDefinitionMap(
+				NewOption("user").Alias("u"),
+				NewOption("password").Sensitive(),
+				NewOption("pin").Type(OptionTypeInt).Sensitive(),
+			),
+			execFunc: func(_ InputOptionsMap, _ io.Writer) error {
+				return execErr
+			},
+		},
+	)
+
+	var logBuffer bytes.Buffer
+	BootstrapWithOptions(
+		args,
+		*registry,
+		BootstrapOptions{
+			IO:          IO{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}},
+			ProcessExit: func(int) {},
+			Logger:      slog.New(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{})),
+		},
+	)
+
+	var logs []commandLog
+	for _, line := range strings.Split(strings.TrimSpace(logBuffer.String()), "\n") {
+		var log commandLog
+		s.Require().NoError(json.Unmarshal([]byte(line), &log))
+		logs = append(logs, log)
+	}
+	return logs
+}
+
+func (s *LoggingSuite) TestBootstrapLogsCommandStartAndFinish() {
+	logs := s.bootstrap([]string{"connect", "-u", "admin", "--password=secret"}, nil)
+
+	s.Require().Len(logs, 2)
+	s.Equal("INFO", logs[0].Level)
+	s.Equal(CommandStartLogMessage, logs[0].Msg)
+	s.Equal("connect", logs[0].Command)
+	s.Equal(map[string]string{"user": "admin", "password": redactedValue}, logs[0].Options)
+
+	s.Equal("INFO", logs[1].Level)
+	s.Equal(CommandFinishLogMessage, logs[1].Msg)
+	s.Equal("connect", logs[1].Command)
+	s.NotEmpty(logs[1].Duration)
+	s.Require().NotNil(logs[1].Status)
+	s.Equal(StatusOk, *logs[1].Status)
+	s.Empty(logs[1].Error)
+}
+
+func (s *LoggingSuite) TestBootstrapLogsCommandFailures() {
+	tests := []struct {
+		name       string
+		args       []string
+		execErr    error
+		wantStatus int
+		wantError  string
+	}{
+		{"Failed command", []string{"connect"}, errors.New("refused"), StatusErr, "refused"},
+		{"Invalid option", []string{"connect", "--port=1"}, nil, StatusUsage, "port"},
+		{"Unknown command", []string{"disconnect"}, nil, StatusUsage, "does not exist"},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				logs := s.bootstrap(scenario.args, scenario.execErr)
+
+				s.Require().Len(logs, 2)
+				s.Equal(scenario.args[0], logs[1].Command)
+				s.Equal("ERROR", logs[1].Level)
+				s.Require().NotNil(logs[1].Status)
+				s.Equal(scenario.wantStatus, *logs[1].Status)
+				s.Contains(logs[1].Error, scenario.wantError)
+			},
+		)
+	}
+}
+
+func (s *LoggingSuite) TestBootstrapLogsTheBareCommandError() {
+	logs := s.bootstrap([]string{"connect"}, errors.New("connection refused"))
+
+	s.Require().Len(logs, 2)
+	s.Equal("connection refused", logs[1].Error)
+}
+
+func (s *LoggingSuite) TestBootstrapKeepsSensitiveValuesOutOfErrors() {
+	logs := s.bootstrap([]string{"connect", "--pin=12ab34"}, nil)
+
+	s.Require().Len(logs, 2)
+	s.Equal(redactedValue, logs[0].Options["pin"])
+	s.Equal("option 'pin' expects an integer value", logs[1].Error)
+}
+
+func (s *LoggingSuite) TestBootstrapRedactsUndeclaredOptions() {
+	logs := s.bootstrap([]string{"connect", "-u", "admin", "--pasword=hunter2"}, nil)
+
+	s.Require().Len(logs, 2)
+	s.Equal(map[string]string{"user": "admin", "pasword": redactedValue}, logs[0].Options)
+	s.NotContains(logs[1].Error, "hunter2")
+}
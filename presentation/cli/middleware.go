@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"context"
+)
+
+// CommandHandler executes a command invocation. Invocation.Command is the command being
+// executed, Invocation.Options and Invocation.Arguments hold its validated input.
+type CommandHandler func(ctx context.Context, invocation Invocation) error
+
+// CommandMiddleware wraps the execution of commands, for cross-cutting concerns like logging,
+// timing, tracing, auth checks or locking. It runs after the command input was built and
+// validated, and must call next to execute the command. The error returned by next is the
+// command result.
+type CommandMiddleware func(next CommandHandler) CommandHandler
+
+// chainMiddlewares wraps the handler with the middlewares, so the first one runs first
+func chainMiddlewares(handler CommandHandler, middlewares []CommandMiddleware) CommandHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// execCommand is the innermost handler, calling the command method matching the optional
+// interfaces it implements
+func execCommand(ctx context.Context, invocation Invocation) error {
+	switch typedCmd := invocation.Command.(type) {
+	case ResultCommand:
+		result, err := typedCmd.ExecResult(ctx, invocation)
+		if err != nil {
+			return err
+		}
+		return renderResult(invocation.Stdout, invocation.Output, result)
+	case ContextCommand:
+		return typedCmd.ExecContext(ctx, invocation)
+	case ArgumentsCommand:
+		return typedCmd.ExecWithArguments(
+			invocation.Arguments,
+			invocation.Options,
+			invocation.Stdout,
+		)
+	default:
+		return invocation.Command.Exec(invocation.Options, invocation.Stdout)
+	}
+}
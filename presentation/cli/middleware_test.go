@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/rsgcata/gocommon/params"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"testing"
+)
+
+type MiddlewareSuite struct {
+	suite.Suite
+}
+
+func TestMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(MiddlewareSuite))
+}
+
+// recordingMiddleware appends the middleware name to calls before and after the command
+func recordingMiddleware(name string, calls *[]string) CommandMiddleware {
+	return func(next CommandHandler) CommandHandler {
+		return func(ctx context.Context, invocation Invocation) error {
+			*calls = append(*calls, name+" before "+invocation.Command.Id())
+			err := next(ctx, invocation)
+			*calls = append(*calls, name+" after")
+			return err
+		}
+	}
+}
+
+func (s *MiddlewareSuite) TestMiddlewaresRunInOrderAroundCommands() {
+	var calls []string
+	cmdErr := errors.New("failed")
+	registry := NewCommandsRegistry()
+	_ = registry.Register(
+		&bootstrapMockCommand{
+			id:       "import",
+			inputDef: MustInputOptionDefinitionMap(NewOption("limit").Type(OptionTypeInt)),
+			execFunc: func(_ InputOptionsMap, _ io.Writer) error {
+				calls = append(calls, "command")
+				return cmdErr
+			},
+		},
+	)
+	registry.Use(recordingMiddleware("registry", &calls))
+
+	var gotOptions InputOptionsMap
+	var gotErr error
+	inspecting := func(next CommandHandler) CommandHandler {
+		return func(ctx context.Context, invocation Invocation) error {
+			gotOptions = invocation.Options
+			gotErr = next(ctx, invocation)
+			return gotErr
+		}
+	}
+
+	var exitCode int
+	BootstrapWithOptions(
+		[]string{"import", "--limit=5"},
+		*registry,
+		BootstrapOptions{
+			IO:          IO{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}},
+			ProcessExit: func(code int) { exitCode = code },
+			Middlewares: []CommandMiddleware{recordingMiddleware("bootstrap", &calls), inspecting},
+		},
+	)
+
+	s.Equal(StatusErr, exitCode)
+	s.Equal(
+		[]string{
+			"bootstrap before import",
+			"registry before import",
+			"command",
+			"registry after",
+			"bootstrap after",
+		},
+		calls,
+	)
+	s.Equal(params.RawVal("5"), gotOptions["limit"].RawVal())
+	s.ErrorIs(gotErr, cmdErr)
+}
+
+func (s *MiddlewareSuite) TestMiddlewaresCanStopTheExecution() {
+	executed := false
+	cmd := &bootstrapMockCommand{
+		id:       "drop",
+		inputDef: InputOptionDefinitionMap{},
+		execFunc: func(_ InputOptionsMap, _ io.Writer) error {
+			executed = true
+			return nil
+		},
+	}
+	denyErr := errors.New("not allowed")
+	deny := func(next CommandHandler) CommandHandler {
+		return func(ctx context.Context, invocation Invocation) error {
+			return denyErr
+		}
+	}
+
+	err := runCommand(
+		context.Background(),
+		cmd,
+		nil,
+		IO{Stdout: &bytes.Buffer{}},
+		runSettings{middlewares: []CommandMiddleware{deny}},
+	)
+
+	s.ErrorIs(err, denyErr)
+	s.False(executed)
+}
+
+func (s *MiddlewareSuite) TestMiddlewaresDoNotRunForInvalidInput() {
+	var calls []string
+	cmd := &bootstrapMockCommand{
+		id:       "import",
+		inputDef: MustInputOptionDefinitionMap(NewOption("limit").Type(OptionTypeInt)),
+	}
+
+	err := runCommand(
+		context.Background(),
+		cmd,
+		[]string{"--limit=abc"},
+		IO{Stdout: &bytes.Buffer{}},
+		runSettings{middlewares: []CommandMiddleware{recordingMiddleware("first", &calls)}},
+	)
+
+	var usageErr *UsageError
+	s.ErrorAs(err, &usageErr)
+	s.Empty(calls)
+}
+
+func (s *MiddlewareSuite) TestMiddlewaresCanWrapAllCommandKinds() {
+	var calls []string
+	middlewares := []CommandMiddleware{recordingMiddleware("m", &calls)}
+	commands := []Command{
+		&bootstrapMockCommand{id: "plain", inputDef: InputOptionDefinitionMap{}},
+		&argumentsMockCommand{
+			bootstrapMockCommand: bootstrapMockCommand{
+				id:       "arguments",
+				inputDef: InputOptionDefinitionMap{},
+			},
+		},
+		&contextMockCommand{
+			bootstrapMockCommand: bootstrapMockCommand{
+				id:       "context",
+				inputDef: InputOptionDefinitionMap{},
+			},
+		},
+	}
+
+	for _, cmd := range commands {
+		s.NoError(
+			runCommand(
+				context.Background(),
+				cmd,
+				nil,
+				IO{Stdout: &bytes.Buffer{}},
+				runSettings{middlewares: middlewares},
+			),
+		)
+	}
+
+	s.Equal(
+		[]string{
+			"m before plain", "m after",
+			"m before arguments", "m after",
+			"m before context", "m after",
+		},
+		calls,
+	)
+}
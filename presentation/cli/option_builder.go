@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+var optionNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+var envVarNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// NewInputOptionDefinition creates an option definition which can be used by Command
+// implementations to declare their input. Returns an error if the name is not a valid
+// option name (letters, digits, "-" and "_", not starting with "-" or "_").
+func NewInputOptionDefinition(
+	name string,
+	description string,
+	required bool,
+	defaultVal string,
+) (InputOptionDefinition, error) {
+	builder := NewOption(name).Description(description).Default(defaultVal)
+	if required {
+		builder.Required()
+	}
+	return builder.Build()
+}
+
+// InputOptionDefinitionBuilder is a fluent builder for InputOptionDefinition. Use NewOption
+// to create one.
+type InputOptionDefinitionBuilder struct {
+	definition InputOptionDefinition
+}
+
+// NewOption starts building an option definition with the given name. The name is the one
+// used on the command line, without the leading "--".
+func NewOption(name string) *InputOptionDefinitionBuilder {
+	return &InputOptionDefinitionBuilder{definition: InputOptionDefinition{name: name}}
+}
+
+func (builder *InputOptionDefinitionBuilder) Description(
+	description string,
+) *InputOptionDefinitionBuilder {
+	builder.definition.description = description
+	return builder
+}
+
+func (builder *InputOptionDefinitionBuilder) Required() *InputOptionDefinitionBuilder {
+	builder.definition.required = true
+	return builder
+}
+
+func (builder *InputOptionDefinitionBuilder) Default(
+	defaultVal string,
+) *InputOptionDefinitionBuilder {
+	builder.definition.defaultVal = defaultVal
+	return builder
+}
+
+// Type sets the type of value the option accepts. Defaults to OptionTypeString.
+func (builder *InputOptionDefinitionBuilder) Type(
+	valueType OptionType,
+) *InputOptionDefinitionBuilder {
+	builder.definition.valueType = valueType
+	return builder
+}
+
+// Enum makes the option an OptionTypeEnum option which accepts only the provided values.
+func (builder *InputOptionDefinitionBuilder) Enum(values ...string) *InputOptionDefinitionBuilder {
+	builder.definition.valueType = OptionTypeEnum
+	builder.definition.enumValues = values
+	return builder
+}
+
+// Flag makes the option a boolean flag, which is the same as Type(OptionTypeBool)
+func (builder *InputOptionDefinitionBuilder) Flag() *InputOptionDefinitionBuilder {
+	builder.definition.valueType = OptionTypeBool
+	return builder
+}
+
+// Alias adds alternative names for the option. Single character aliases are used with a
+// single dash (-v) and can be grouped (-abc), the others are used with two dashes.
+func (builder *InputOptionDefinitionBuilder) Alias(
+	aliases ...string,
+) *InputOptionDefinitionBuilder {
+	builder.definition.aliases = append(builder.definition.aliases, aliases...)
+	return builder
+}
+
+// Repeatable allows the option to be provided more than once (--tag=a --tag=b). Every value
+// is available through InputOption.RawVals.
+func (builder *InputOptionDefinitionBuilder) Repeatable() *InputOptionDefinitionBuilder {
+	builder.definition.repeatable = true
+	return builder
+}
+
+// List makes the option repeatable and splits each of its values by params.ListSeparator, so
+// --ids=1,2 --ids=3 holds the values 1, 2 and 3. The option type applies to every value.
+func (builder *InputOptionDefinitionBuilder) List() *InputOptionDefinitionBuilder {
+	builder.definition.list = true
+	return builder
+}
+
+// Sensitive marks the option as holding a secret, like a password. Its value is read without
+// echo when the user is prompted for it.
+func (builder *InputOptionDefinitionBuilder) Sensitive() *InputOptionDefinitionBuilder {
+	builder.definition.sensitive = true
+	return builder
+}
+
+// Env binds the option to an environment variable. When the option is not provided on the
+// command line, the variable value is used before the default value.
+func (builder *InputOptionDefinitionBuilder) Env(envVar string) *InputOptionDefinitionBuilder {
+	builder.definition.envVar = envVar
+	return builder
+}
+
+// Build validates and returns the option definition.
+func (builder *InputOptionDefinitionBuilder) Build() (InputOptionDefinition, error) {
+	def := builder.definition
+	if !optionNamePattern.MatchString(def.name) {
+		return InputOptionDefinition{}, fmt.Errorf(
+			"option name '%s' is invalid, only letters, digits, '-' and '_' are allowed"+
+				" and it must start with a letter or digit",
+			def.name,
+		)
+	}
+
+	for _, alias := range def.aliases {
+		if !optionNamePattern.MatchString(alias) || alias == def.name {
+			return InputOptionDefinition{}, fmt.Errorf(
+				"alias '%s' of option '%s' is invalid",
+				alias,
+				def.name,
+			)
+		}
+	}
+
+	if def.envVar != "" && !envVarNamePattern.MatchString(def.envVar) {
+		return InputOptionDefinition{}, fmt.Errorf(
+			"environment variable '%s' of option '%s' is invalid",
+			def.envVar,
+			def.name,
+		)
+	}
+
+	if def.valueType < OptionTypeString || def.valueType > OptionTypeEnum {
+		return InputOptionDefinition{}, fmt.Errorf(
+			"option '%s' has an unknown type %s",
+			def.name,
+			def.valueType,
+		)
+	}
+
+	if def.valueType == OptionTypeEnum && len(def.enumValues) == 0 {
+		return InputOptionDefinition{}, fmt.Errorf(
+			"option '%s' is an enum but has no allowed values",
+			def.name,
+		)
+	}
+
+	if def.IsFlag() && def.IsRepeatable() {
+		return InputOptionDefinition{}, fmt.Errorf(
+			"option '%s' is a flag and cannot be repeatable",
+			def.name,
+		)
+	}
+
+	if def.required && def.defaultVal != "" {
+		return InputOptionDefinition{}, fmt.Errorf(
+			"option '%s' cannot be required and have a default value at the same time",
+			def.name,
+		)
+	}
+
+	if err := def.validateValues(def.splitValue(def.defaultVal)); err != nil {
+		return InputOptionDefinition{}, fmt.Errorf("invalid default value: %w", err)
+	}
+
+	def.enumValues = slices.Clone(def.enumValues)
+	def.aliases = slices.Clone(def.aliases)
+	return def, nil
+}
+
+// NewInputOptionDefinitionMap builds all the provided option definitions and collects them
+// in a map keyed by option name. All validation errors, including duplicate names, are
+// returned joined together.
+func NewInputOptionDefinitionMap(
+	builders ...*InputOptionDefinitionBuilder,
+) (InputOptionDefinitionMap, error) {
+	definitions := make(InputOptionDefinitionMap, len(builders))
+	usedNames := map[string]bool{}
+	var errs []error
+	for _, builder := range builders {
+		definition, err := builder.Build()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, name := range append([]string{definition.name}, definition.aliases...) {
+			if usedNames[name] {
+				err = errors.Join(err, fmt.Errorf("option '%s' is defined twice", name))
+			}
+			usedNames[name] = true
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		definitions[definition.name] = definition
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return definitions, nil
+}
+
+// MustInputOptionDefinitionMap is like NewInputOptionDefinitionMap but panics on error. It is
+// meant to be used in Command.InputDefinition implementations, where the definitions are
+// static and an invalid one is a programming error.
+func MustInputOptionDefinitionMap(
+	builders ...*InputOptionDefinitionBuilder,
+) InputOptionDefinitionMap {
+	definitions, err := NewInputOptionDefinitionMap(builders...)
+	if err != nil {
+		panic(err)
+	}
+	return definitions
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type OptionBuilderSuite struct {
+	suite.Suite
+}
+
+func TestOptionBuilderSuite(t *testing.T) {
+	suite.Run(t, new(OptionBuilderSuite))
+}
+
+func (s *OptionBuilderSuite) TestItCanBuildOptionDefinitions() {
+	def, err := NewOption("dry-run").
+		Description("Do not persist anything").
+		Default("false").
+		Build()
+
+	s.NoError(err)
+	s.Equal("dry-run", def.Name())
+	s.Equal("Do not persist anything", def.Description())
+	s.False(def.Required())
+	s.Equal("false", def.DefaultValue())
+
+	def, err = NewOption("name").Required().Build()
+	s.NoError(err)
+	s.True(def.Required())
+
+	def, err = NewInputOptionDefinition("limit", "Max items", false, "10")
+	s.NoError(err)
+	s.Equal("limit", def.Name())
+	s.Equal("Max items", def.Description())
+	s.False(def.Required())
+	s.Equal("10", def.DefaultValue())
+}
+
+func (s *OptionBuilderSuite) TestItFailsToBuildOptionsWithInvalidNames() {
+	invalidNames := []string{"", "-name", "_name", "na me", "name=val", "näme", "--name"}
+	for _, name := range invalidNames {
+		s.Run(
+			name, func() {
+				_, err := NewOption(name).Build()
+				s.Error(err, "Build() should fail for name %q", name)
+
+				_, err = NewInputOptionDefinition(name, "", false, "")
+				s.Error(err, "NewInputOptionDefinition() should fail for name %q", name)
+			},
+		)
+	}
+
+	validNames := []string{"a", "name", "name-with-dash", "name_with_underscore", "9lives"}
+	for _, name := range validNames {
+		s.Run(
+			name, func() {
+				_, err := NewOption(name).Build()
+				s.NoError(err, "Build() should succeed for name %q", name)
+			},
+		)
+	}
+}
+
+func (s *OptionBuilderSuite) TestItCanBuildOptionDefinitionsWithAliases() {
+	def, err := NewOption("verbose").Alias("v", "verb").Build()
+	s.NoError(err)
+	s.Equal([]string{"v", "verb"}, def.Aliases())
+
+	_, err = NewOption("verbose").Alias("-v").Build()
+	s.Error(err)
+
+	_, err = NewOption("verbose").Alias("verbose").Build()
+	s.Error(err)
+
+	_, err = NewInputOptionDefinitionMap(
+		NewOption("verbose").Alias("v"),
+		NewOption("version").Alias("v"),
+	)
+	s.Error(err)
+	s.Contains(err.Error(), "option 'v' is defined twice")
+
+	_, err = NewInputOptionDefinitionMap(
+		NewOption("verbose"),
+		NewOption("version").Alias("verbose"),
+	)
+	s.Error(err)
+}
+
+func (s *OptionBuilderSuite) TestItCanBuildTypedOptionDefinitions() {
+	def, err := NewOption("port").Type(OptionTypeInt).Default("8080").Build()
+	s.NoError(err)
+	s.Equal(OptionTypeInt, def.Type())
+
+	def, err = NewOption("mode").Enum("fast", "slow").Default("slow").Build()
+	s.NoError(err)
+	s.Equal(OptionTypeEnum, def.Type())
+	s.Equal([]string{"fast", "slow"}, def.EnumValues())
+
+	def, err = NewOption("name").Build()
+	s.NoError(err)
+	s.Equal(OptionTypeString, def.Type())
+}
+
+func (s *OptionBuilderSuite) TestItFailsToBuildInvalidTypedOptionDefinitions() {
+	tests := []struct {
+		name    string
+		builder *InputOptionDefinitionBuilder
+	}{
+		{"Required with default", NewOption("mode").Required().Default("fast")},
+		{"Enum without values", NewOption("mode").Enum()},
+		{"Unknown type", NewOption("mode").Type(OptionType(99))},
+		{"Invalid int default", NewOption("port").Type(OptionTypeInt).Default("abc")},
+		{"Invalid bool default", NewOption("force").Type(OptionTypeBool).Default("abc")},
+		{"Invalid enum default", NewOption("mode").Enum("fast").Default("slow")},
+		{"Invalid duration default", NewOption("wait").Type(OptionTypeDuration).Default("1x")},
+		{"Invalid env var name", NewOption("port").Env("APP-PORT")},
+		{"Repeatable flag", NewOption("force").Flag().Repeatable()},
+		{"Invalid list default", NewOption("ids").Type(OptionTypeInt).List().Default("1,x")},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				_, err := scenario.builder.Build()
+				s.Error(err)
+			},
+		)
+	}
+}
+
+func (s *OptionBuilderSuite) TestItCanBuildOptionDefinitionMaps() {
+	definitions, err := NewInputOptionDefinitionMap(
+		NewOption("first").Description("First option"),
+		NewOption("second").Required(),
+	)
+
+	s.NoError(err)
+	s.Len(definitions, 2)
+	s.Equal("First option", definitions["first"].Description())
+	s.True(definitions["second"].Required())
+
+	s.NotPanics(
+		func() {
+			definitions = MustInputOptionDefinitionMap(NewOption("first"))
+		},
+	)
+	s.Len(definitions, 1)
+}
+
+func (s *OptionBuilderSuite) TestItFailsToBuildOptionDefinitionMapsWithInvalidDefinitions() {
+	_, err := NewInputOptionDefinitionMap(
+		NewOption("first"),
+		NewOption("first"),
+		NewOption("in valid"),
+	)
+
+	s.Error(err)
+	s.Contains(err.Error(), "option 'first' is defined twice")
+	s.Contains(err.Error(), "option name 'in valid' is invalid")
+
+	s.Panics(
+		func() {
+			MustInputOptionDefinitionMap(NewOption("first"), NewOption("first"))
+		},
+	)
+}
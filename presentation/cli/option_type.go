@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/rsgcata/gocommon/params"
+	"slices"
+	"strings"
+)
+
+// OptionType is the type of value an option accepts. Values are checked against it by
+// BuildOptionsFrom before the command is executed.
+type OptionType int
+
+const (
+	OptionTypeString OptionType = iota
+	OptionTypeInt
+	OptionTypeBool
+	OptionTypeFloat
+	OptionTypeDuration
+	OptionTypeEnum
+)
+
+func (optionType OptionType) String() string {
+	switch optionType {
+	case OptionTypeString:
+		return "string"
+	case OptionTypeInt:
+		return "int"
+	case OptionTypeBool:
+		return "bool"
+	case OptionTypeFloat:
+		return "float"
+	case OptionTypeDuration:
+		return "duration"
+	case OptionTypeEnum:
+		return "enum"
+	default:
+		return fmt.Sprintf("OptionType(%d)", int(optionType))
+	}
+}
+
+// validateValue checks if the provided raw value can be parsed as the type declared by the
+// option definition. Empty values are considered valid, lacksValue and the required check
+// handle them.
+func (def InputOptionDefinition) validateValue(rawVal string) error {
+	val := params.RawVal(rawVal)
+	if strings.TrimSpace(rawVal) == "" {
+		return nil
+	}
+
+	var invalid bool
+	var expected string
+	switch def.valueType {
+	case OptionTypeInt:
+		_, invalid = val.GetAsInt(0)
+		expected = "an integer value"
+	case OptionTypeBool:
+		_, invalid = val.GetAsBool(false)
+		expected = "a boolean value"
+	case OptionTypeFloat:
+		_, invalid = val.GetAsFloat(0)
+		expected = "a numeric value"
+	case OptionTypeDuration:
+		_, invalid = val.GetAsDuration(0)
+		expected = "a duration value (e.g. 1h30m)"
+	case OptionTypeEnum:
+		parsedVal, _ := val.GetAsString("")
+		invalid = !slices.Contains(def.enumValues, parsedVal)
+		expected = "one of " + strings.Join(def.enumValues, ", ")
+	default:
+		return nil
+	}
+
+	if invalid && def.sensitive {
+		// The value of sensitive options is kept out of the error, which is written and logged
+		return fmt.Errorf("option '%s' expects %s", def.name, expected)
+	}
+	if invalid {
+		return fmt.Errorf(
+			"option '%s' expects %s, got '%s'",
+			def.name,
+			expected,
+			rawVal,
+		)
+	}
+	return nil
+}
+
+// lacksValue checks if the option was provided without a value, like "--port" with nothing
+// after it. Only string options accept empty values.
+func (def InputOptionDefinition) lacksValue(values []string) bool {
+	return def.valueType != OptionTypeString && (len(values) == 0 || slices.Contains(values, ""))
+}
+
+// validateValues validates every value of the option and returns the first error
+func (def InputOptionDefinition) validateValues(values []string) error {
+	for _, value := range values {
+		if err := def.validateValue(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// valuePlaceholder describes the expected value in help output, like <int> or <a|b>.
+func (def InputOptionDefinition) valuePlaceholder() string {
+	if def.valueType == OptionTypeEnum {
+		return "<" + strings.Join(def.enumValues, "|") + ">"
+	}
+	return "<" + def.valueType.String() + ">"
+}
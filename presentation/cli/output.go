@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// OutputFormat is the format used to render the results of ResultCommands, chosen with the
+// global --output option
+type OutputFormat string
+
+const (
+	OutputTable OutputFormat = "table"
+	OutputJson  OutputFormat = "json"
+	OutputYaml  OutputFormat = "yaml"
+	OutputCsv   OutputFormat = "csv"
+)
+
+// outputFormats are the supported output formats, the first one being the default
+var outputFormats = []OutputFormat{OutputTable, OutputJson, OutputYaml, OutputCsv}
+
+// ResultCommand is implemented by commands returning structured results instead of writing
+// their output. Bootstrap calls ExecResult instead of the other Exec methods and renders the
+// result according to the --output option, so scripts can consume any command as JSON, YAML
+// or CSV. The result is a Table, or any value which can be encoded as JSON, like a struct, a
+// map or a slice of them.
+type ResultCommand interface {
+	Command
+	ExecResult(ctx context.Context, invocation Invocation) (any, error)
+}
+
+// Table is a result made of rows sharing the same columns. It is rendered as an aligned table,
+// as CSV with a header line or as a list of objects keyed by column in JSON and YAML.
+type Table struct {
+	Columns []string
+	Rows    [][]any
+}
+
+func NewTable(columns ...string) *Table {
+	return &Table{Columns: columns}
+}
+
+// AddRow appends a row, with a value for each column in order
+func (table *Table) AddRow(values ...any) *Table {
+	table.Rows = append(table.Rows, values)
+	return table
+}
+
+// MarshalJSON encodes the rows as objects, keeping the keys in the order of the columns
+func (table *Table) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString("[")
+	for i, row := range table.Rows {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString("{")
+		for j, column := range table.Columns {
+			var value any
+			if j < len(row) {
+				value = row[j]
+			}
+			encodedColumn, err := json.Marshal(column)
+			if err != nil {
+				return nil, err
+			}
+			encodedValue, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			if j > 0 {
+				buffer.WriteString(",")
+			}
+			buffer.Write(encodedColumn)
+			buffer.WriteString(":")
+			buffer.Write(encodedValue)
+		}
+		buffer.WriteString("}")
+	}
+	buffer.WriteString("]")
+	return buffer.Bytes(), nil
+}
+
+// renderResult writes the result of a ResultCommand in the requested format
+func renderResult(writer io.Writer, format OutputFormat, result any) error {
+	if table, isTable := result.(Table); isTable {
+		result = &table
+	}
+
+	var err error
+	switch format {
+	case OutputJson:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(result)
+	case OutputYaml:
+		err = writeYaml(writer, result)
+	case OutputCsv:
+		err = writeCsv(writer, result)
+	default:
+		err = writeTable(writer, result)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to render the result as %s: %w", format, err)
+	}
+	return nil
+}
+
+// writeYaml writes the result as YAML. The result is encoded as JSON first, so the JSON
+// field names and the order of the keys are the same in both formats.
+func writeYaml(writer io.Writer, result any) error {
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	var document yaml.Node
+	if err = yaml.Unmarshal(encoded, &document); err != nil {
+		return err
+	}
+	resetYamlStyle(&document)
+
+	encoder := yaml.NewEncoder(writer)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(&document); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// resetYamlStyle drops the JSON flow style and quoting kept by the YAML parser, so the nodes
+// are written in block style
+func resetYamlStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYamlStyle(child)
+	}
+}
+
+func writeTable(writer io.Writer, result any) error {
+	table, err := tableOf(result)
+	if err != nil {
+		return err
+	}
+	if len(table.Columns) == 0 {
+		return nil
+	}
+
+	tableWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tableWriter, strings.Join(table.Columns, "\t"))
+	for _, row := range tableCells(table) {
+		_, _ = fmt.Fprintln(tableWriter, strings.Join(row, "\t"))
+	}
+	return tableWriter.Flush()
+}
+
+func writeCsv(writer io.Writer, result any) error {
+	table, err := tableOf(result)
+	if err != nil {
+		return err
+	}
+	if len(table.Columns) == 0 {
+		return nil
+	}
+
+	csvWriter := csv.NewWriter(writer)
+	if err = csvWriter.Write(table.Columns); err != nil {
+		return err
+	}
+	if err = csvWriter.WriteAll(tableCells(table)); err != nil {
+		return err
+	}
+	return csvWriter.Error()
+}
+
+// tableOf converts the result to a table. Values other than a Table are converted through
+// their JSON encoding: an object is a single row, an array has a row per element, and the
+// object keys, sorted, are the columns. Scalar values are placed in a "value" column. A nil
+// Table is an empty table.
+func tableOf(result any) (*Table, error) {
+	if table, isTable := result.(*Table); isTable {
+		if table == nil {
+			return &Table{}, nil
+		}
+		return table, nil
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var decoded any
+	if err = decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	items, isArray := decoded.([]any)
+	if !isArray {
+		if decoded == nil {
+			return &Table{}, nil
+		}
+		items = []any{decoded}
+	}
+
+	objects := make([]map[string]any, len(items))
+	var columns []string
+	for i, item := range items {
+		object, isObject := item.(map[string]any)
+		if !isObject {
+			object = map[string]any{"value": item}
+		}
+		for key := range object {
+			if !slices.Contains(columns, key) {
+				columns = append(columns, key)
+			}
+		}
+		objects[i] = object
+	}
+	slices.Sort(columns)
+
+	table := NewTable(columns...)
+	for _, object := range objects {
+		row := make([]any, len(columns))
+		for i, column := range columns {
+			row[i] = object[column]
+		}
+		table.AddRow(row...)
+	}
+	return table, nil
+}
+
+// tableCells formats the values of the table rows, with a cell for each column
+func tableCells(table *Table) [][]string {
+	cells := make([][]string, len(table.Rows))
+	for i, row := range table.Rows {
+		cells[i] = make([]string, len(table.Columns))
+		for j := range table.Columns {
+			if j < len(row) {
+				cells[i][j] = cellText(row[j])
+			}
+		}
+	}
+	return cells
+}
+
+// cellText formats a value for the table and CSV formats. Nested objects and arrays are
+// written as compact JSON.
+func cellText(value any) string {
+	switch typedValue := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typedValue
+	case json.Number:
+		return typedValue.String()
+	case map[string]any, []any:
+		encoded, err := json.Marshal(typedValue)
+		if err != nil {
+			return fmt.Sprint(typedValue)
+		}
+		return string(encoded)
+	default:
+		return fmt.Sprint(typedValue)
+	}
+}
@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type OutputSuite struct {
+	suite.Suite
+}
+
+func TestOutputSuite(t *testing.T) {
+	suite.Run(t, new(OutputSuite))
+}
+
+// Mock command returning a structured result
+type resultMockCommand struct {
+	bootstrapMockCommand
+	result    any
+	resultErr error
+}
+
+func (m *resultMockCommand) ExecResult(_ context.Context, _ Invocation) (any, error) {
+	return m.result, m.resultErr
+}
+
+type user struct {
+	Name  string   `json:"name"`
+	Age   int      `json:"age"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+func (s *OutputSuite) TestItRendersTables() {
+	table := NewTable("name", "age", "email").
+		AddRow("alice", 31, "alice@example.com").
+		AddRow("bob, jr", 7)
+
+	tests := []struct {
+		name   string
+		format OutputFormat
+		want   string
+	}{
+		{
+			"Table",
+			OutputTable,
+			"name     age  email\n" +
+				"alice    31   alice@example.com\n" +
+				"bob, jr  7    \n",
+		},
+		{
+			"Json",
+			OutputJson,
+			"[\n" +
+				"  {\n" +
+				"    \"name\": \"alice\",\n" +
+				"    \"age\": 31,\n" +
+				"    \"email\": \"alice@example.com\"\n" +
+				"  },\n" +
+				"  {\n" +
+				"    \"name\": \"bob, jr\",\n" +
+				"    \"age\": 7,\n" +
+				"    \"email\": null\n" +
+				"  }\n" +
+				"]\n",
+		},
+		{
+			"Yaml",
+			OutputYaml,
+			"- name: alice\n" +
+				"  age: 31\n" +
+				"  email: alice@example.com\n" +
+				"- name: bob, jr\n" +
+				"  age: 7\n" +
+				"  email: null\n",
+		},
+		{
+			"Csv",
+			OutputCsv,
+			"name,age,email\n" +
+				"alice,31,alice@example.com\n" +
+				"\"bob, jr\",7,\n",
+		},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				var buf bytes.Buffer
+				s.Require().NoError(renderResult(&buf, scenario.format, table))
+				s.Equal(scenario.want, buf.String())
+
+				buf.Reset()
+				s.Require().NoError(renderResult(&buf, scenario.format, *table))
+				s.Equal(scenario.want, buf.String(), "tables can be returned by value")
+			},
+		)
+	}
+}
+
+func (s *OutputSuite) TestItRendersNilTablesAsEmptyTables() {
+	var table *Table
+	tests := []struct {
+		format OutputFormat
+		want   string
+	}{
+		{OutputTable, ""},
+		{OutputCsv, ""},
+		{OutputJson, "null\n"},
+		{OutputYaml, "null\n"},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			string(scenario.format), func() {
+				var buf bytes.Buffer
+				s.Require().NoError(renderResult(&buf, scenario.format, table))
+				s.Equal(scenario.want, buf.String())
+			},
+		)
+	}
+}
+
+func (s *OutputSuite) TestItRendersObjects() {
+	users := []user{{"alice", 31, []string{"admin", "dev"}}, {"bob", 7, nil}}
+
+	tests := []struct {
+		name   string
+		format OutputFormat
+		result any
+		want   string
+	}{
+		{
+			"Table of objects",
+			OutputTable,
+			users,
+			"age  name   roles\n" +
+				"31   alice  [\"admin\",\"dev\"]\n" +
+				"7    bob    \n",
+		},
+		{
+			"Csv of a single object",
+			OutputCsv,
+			users[1],
+			"age,name\n7,bob\n",
+		},
+		{
+			"Table of scalars",
+			OutputTable,
+			[]string{"a", "b"},
+			"value\na\nb\n",
+		},
+		{
+			"Table of nothing",
+			OutputTable,
+			nil,
+			"",
+		},
+		{
+			"Yaml keeps the json field names and order",
+			OutputYaml,
+			users,
+			"- name: alice\n" +
+				"  age: 31\n" +
+				"  roles:\n" +
+				"    - admin\n" +
+				"    - dev\n" +
+				"- name: bob\n" +
+				"  age: 7\n",
+		},
+		{
+			"Json",
+			OutputJson,
+			users[1],
+			"{\n  \"name\": \"bob\",\n  \"age\": 7\n}\n",
+		},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				var buf bytes.Buffer
+				s.Require().NoError(renderResult(&buf, scenario.format, scenario.result))
+				s.Equal(scenario.want, buf.String())
+			},
+		)
+	}
+
+	err := renderResult(&bytes.Buffer{}, OutputJson, make(chan int))
+	s.ErrorContains(err, "failed to render the result as json")
+}
+
+func (s *OutputSuite) TestBootstrapRendersResultsInRequestedFormat() {
+	cmd := &resultMockCommand{
+		bootstrapMockCommand: bootstrapMockCommand{
+			id:       "users",
+			inputDef: InputOptionDefinitionMap{},
+		},
+		result: []user{{Name: "alice", Age: 31}},
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		resultErr  error
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{"Default format", []string{"users"}, nil, StatusOk, "age  name\n31   alice\n", ""},
+		{
+			"Csv format",
+			[]string{"users", "--output=csv"},
+			nil,
+			StatusOk,
+			"age,name\n31,alice\n",
+			"",
+		},
+		{
+			"Json format",
+			[]string{"users", "--output", "json"},
+			nil,
+			StatusOk,
+			"[\n  {\n    \"name\": \"alice\",\n    \"age\": 31\n  }\n]\n",
+			"",
+		},
+		{
+			"Invalid format",
+			[]string{"users", "--output=xml"},
+			nil,
+			StatusUsage,
+			"",
+			"expects one of table, json, yaml, csv",
+		},
+		{"Command error", []string{"users"}, errors.New("db down"), StatusErr, "", "db down"},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				cmd.resultErr = scenario.resultErr
+				registry := NewCommandsRegistry()
+				_ = registry.Register(cmd)
+
+				var stdout, stderr bytes.Buffer
+				var exitCode int
+				BootstrapWithOptions(
+					scenario.args,
+					*registry,
+					BootstrapOptions{
+						IO:          IO{Stdout: &stdout, Stderr: &stderr},
+						ProcessExit: func(code int) { exitCode = code },
+					},
+				)
+
+				s.Equal(scenario.wantCode, exitCode)
+				s.Equal(scenario.wantStdout, stdout.String())
+				s.Contains(stderr.String(), scenario.wantStderr)
+			},
+		)
+	}
+}
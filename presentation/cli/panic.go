@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// StatusPanic is used when the command panicked, following the sysexits convention for
+// internal software errors (EX_SOFTWARE)
+const StatusPanic = 70
+
+// PanicVerbosity controls how much of a recovered panic is written to stderr
+type PanicVerbosity int
+
+const (
+	// PanicVerbosityMessage writes only the panic value. It is the default.
+	PanicVerbosityMessage PanicVerbosity = iota
+	// PanicVerbosityStack writes the panic value followed by the stack trace
+	PanicVerbosityStack
+)
+
+// PanicError is returned when a command (or a middleware) panicked. It keeps the value passed
+// to panic, which can be of any type, and the stack trace of the panicking goroutine.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+// newPanicError must be called from the deferred function recovering the panic, so the stack
+// trace still contains the frames which panicked
+func newPanicError(value any) *PanicError {
+	return &PanicError{Value: value, Stack: debug.Stack()}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("command panicked: %v", e.Value)
+}
+
+// Unwrap returns the panic value when it is an error
+func (e *PanicError) Unwrap() error {
+	if err, isErr := e.Value.(error); isErr {
+		return err
+	}
+	return nil
+}
+
+func (e *PanicError) ExitCode() int {
+	return StatusPanic
+}
+
+// errorDetails returns the bare error message, followed by the stack trace when the error is
+// a PanicError and the verbosity asks for it
+func errorDetails(err error, verbosity PanicVerbosity) string {
+	var panicErr *PanicError
+	if verbosity >= PanicVerbosityStack && errors.As(err, &panicErr) {
+		return bareErrorMessage(err) + "\n" + string(panicErr.Stack)
+	}
+	return bareErrorMessage(err)
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"testing"
+)
+
+type PanicSuite struct {
+	suite.Suite
+}
+
+func TestPanicSuite(t *testing.T) {
+	suite.Run(t, new(PanicSuite))
+}
+
+func panickingCommand(value any) *bootstrapMockCommand {
+	return &bootstrapMockCommand{
+		id:       "crash",
+		inputDef: InputOptionDefinitionMap{},
+		execFunc: func(_ InputOptionsMap, _ io.Writer) error {
+			panic(value)
+		},
+	}
+}
+
+// invalidDefinitionCommand declares the same option twice, so its InputDefinition panics
+type invalidDefinitionCommand struct {
+	bootstrapMockCommand
+}
+
+func (m *invalidDefinitionCommand) InputDefinition() InputOptionDefinitionMap {
+	return MustInputOptionDefinitionMap(NewOption("x"), NewOption("x"))
+}
+
+func (s *PanicSuite) TestRunCommandRecoversAnyPanicValue() {
+	valueErr := errors.New("broken")
+	tests := []struct {
+		name        string
+		value       any
+		wantMessage string
+	}{
+		{"Error value", valueErr, "command panicked: broken"},
+		{"String value", "broken", "command panicked: broken"},
+		{"Int value", 42, "command panicked: 42"},
+		{"Struct value", struct{ Id int }{7}, "command panicked: {7}"},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				err := runCommand(
+					context.Background(),
+					panickingCommand(scenario.value),
+					[]string{},
+					IO{Stdout: &bytes.Buffer{}},
+					runSettings{},
+				)
+
+				var panicErr *PanicError
+				s.Require().ErrorAs(err, &panicErr)
+				s.Equal(scenario.value, panicErr.Value)
+				s.Equal(scenario.wantMessage, err.Error())
+				s.Contains(string(panicErr.Stack), "panickingCommand")
+				s.Equal(StatusPanic, exitCodeOf(err, false))
+				if scenario.value == valueErr {
+					s.ErrorIs(err, valueErr)
+				}
+			},
+		)
+	}
+}
+
+func (s *PanicSuite) TestRunCommandRecoversMiddlewarePanics() {
+	err := runCommand(
+		context.Background(),
+		&bootstrapMockCommand{id: "test", inputDef: InputOptionDefinitionMap{}},
+		[]string{},
+		IO{Stdout: &bytes.Buffer{}},
+		runSettings{
+			middlewares: []CommandMiddleware{
+				func(next CommandHandler) CommandHandler {
+					return func(ctx context.Context, invocation Invocation) error {
+						panic("middleware failure")
+					}
+				},
+			},
+		},
+	)
+
+	var panicErr *PanicError
+	s.Require().ErrorAs(err, &panicErr)
+	s.Equal("middleware failure", panicErr.Value)
+}
+
+func (s *PanicSuite) TestBootstrapWritesPanicsWithConfiguredVerbosity() {
+	tests := []struct {
+		name      string
+		verbosity PanicVerbosity
+		wantStack bool
+	}{
+		{"Message only", PanicVerbosityMessage, false},
+		{"Message and stack", PanicVerbosityStack, true},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				registry := NewCommandsRegistry()
+				_ = registry.Register(panickingCommand("broken"))
+
+				var stderr bytes.Buffer
+				var exitCode int
+				BootstrapWithOptions(
+					[]string{"crash"},
+					*registry,
+					BootstrapOptions{
+						IO:             IO{Stdout: &bytes.Buffer{}, Stderr: &stderr},
+						ProcessExit:    func(code int) { exitCode = code },
+						PanicVerbosity: scenario.verbosity,
+					},
+				)
+
+				s.Equal(StatusPanic, exitCode)
+				s.Contains(
+					stderr.String(),
+					"Failed to execute command crash with error: command panicked: broken\n",
+				)
+				s.Equal(scenario.wantStack, bytes.Contains(stderr.Bytes(), []byte("goroutine")))
+			},
+		)
+	}
+}
+
+func (s *PanicSuite) TestBootstrapRecoversPanicsOfInputDefinitions() {
+	registry := NewCommandsRegistry()
+	_ = registry.Register(
+		&invalidDefinitionCommand{bootstrapMockCommand{id: "invalid"}},
+	)
+
+	for _, args := range [][]string{{"invalid"}, {"invalid", "--help"}} {
+		var stderr bytes.Buffer
+		var exitCode int
+		s.NotPanics(
+			func() {
+				BootstrapWithOptions(
+					args,
+					*registry,
+					BootstrapOptions{
+						IO:          IO{Stdout: &bytes.Buffer{}, Stderr: &stderr},
+						ProcessExit: func(code int) { exitCode = code },
+					},
+				)
+			},
+		)
+
+		s.Equal(StatusPanic, exitCode)
+		s.Contains(stderr.String(), "command panicked: option 'x' is defined twice")
+	}
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// rawOption is an option occurrence found in the command line arguments, before any
+// validation against the option definitions.
+type rawOption struct {
+	name  string
+	value string
+}
+
+// parsedArgs is the result of walking the command line arguments
+type parsedArgs struct {
+	options    []rawOption
+	positional []string
+	// errs holds the malformed arguments, like "--=value" without an option name
+	errs []error
+}
+
+// parseArgs walks the raw command line arguments following the POSIX/GNU conventions:
+//   - "--name=value" and "--name value" for long options (or long aliases)
+//   - "-n value", "-nvalue" and "-n=value" for single character aliases
+//   - "-abc" for grouped single character aliases, where the first alias which takes a value
+//     consumes the rest of the group as its value
+//   - "--flag" sets a boolean flag to true and "--no-flag" sets it to false
+//   - "--" marks the end of options, everything after it is positional
+//
+// A separate value (the next argument) is consumed only by known options which take a value
+// and only if it does not look like an option itself. Negative numbers and a lone "-" are
+// accepted as separate values. Values starting with a dash can always be passed using the
+// "--name=value" form. Aliases are resolved to the option name.
+func parseArgs(args []string, definitions InputOptionDefinitionMap) parsedArgs {
+	index := definitions.index()
+	result := parsedArgs{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			result.positional = append(result.positional, args[i+1:]...)
+			return result
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue := strings.Cut(arg[2:], "=")
+			name = strings.TrimSpace(name)
+			if name == "" {
+				result.errs = append(result.errs, missingOptionNameError(arg))
+				continue
+			}
+			def, known := index[name]
+			if !known && !hasValue {
+				if negatedDef, negated := index[strings.TrimPrefix(name, "no-")]; negated &&
+					negatedDef.isNegatable() {
+					def, known, hasValue, value = negatedDef, true, true, "false"
+				}
+			}
+			if known {
+				name = def.name
+			}
+
+			if !hasValue && known {
+				if def.IsFlag() {
+					value = "true"
+				} else if i+1 < len(args) && looksLikeValue(args[i+1]) {
+					i++
+					value = args[i]
+				}
+			}
+
+			result.options = append(
+				result.options,
+				rawOption{name: name, value: strings.TrimSpace(value)},
+			)
+		case strings.HasPrefix(arg, "-") && arg != "-" && !isNumber(arg):
+			group := arg[1:]
+			if strings.HasPrefix(group, "=") {
+				result.errs = append(result.errs, missingOptionNameError(arg))
+				continue
+			}
+			for j, char := range group {
+				name := string(char)
+				def, known := index[name]
+				if !known {
+					result.options = append(result.options, rawOption{name: name})
+					continue
+				}
+				if def.IsFlag() {
+					result.options = append(
+						result.options,
+						rawOption{name: def.name, value: "true"},
+					)
+					continue
+				}
+
+				attached := group[j+len(name):]
+				value := strings.TrimPrefix(attached, "=")
+				if attached == "" && i+1 < len(args) && looksLikeValue(args[i+1]) {
+					i++
+					value = args[i]
+				}
+				result.options = append(
+					result.options,
+					rawOption{name: def.name, value: strings.TrimSpace(value)},
+				)
+				break
+			}
+		default:
+			result.positional = append(result.positional, arg)
+		}
+	}
+
+	return result
+}
+
+func missingOptionNameError(arg string) error {
+	return fmt.Errorf("option name is missing in '%s'", arg)
+}
+
+// looksLikeValue reports if the argument can be consumed as the value of the previous option
+func looksLikeValue(arg string) bool {
+	return !strings.HasPrefix(arg, "-") || arg == "-" || isNumber(arg)
+}
+
+// isNumber reports if the argument is a plain number like "-5" or "-.5", words like "-Inf"
+// are not considered numbers
+func isNumber(arg string) bool {
+	digits := strings.TrimPrefix(arg, "-")
+	if digits == "" || !strings.ContainsAny(digits[:1], "0123456789.") {
+		return false
+	}
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
+
+// index maps option names and aliases to their definitions
+func (definitions InputOptionDefinitionMap) index() map[string]InputOptionDefinition {
+	index := make(map[string]InputOptionDefinition, len(definitions))
+	for _, def := range definitions {
+		index[def.name] = def
+		for _, alias := range def.aliases {
+			index[alias] = def
+		}
+	}
+	return index
+}
@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ParserSuite struct {
+	suite.Suite
+}
+
+func TestParserSuite(t *testing.T) {
+	suite.Run(t, new(ParserSuite))
+}
+
+func (s *ParserSuite) TestItCanParseArgs() {
+	definitions := MustInputOptionDefinitionMap(
+		NewOption("name").Alias("n", "nm"),
+		NewOption("port").Alias("p").Type(OptionTypeInt),
+		NewOption("verbose").Alias("v").Type(OptionTypeBool),
+		NewOption("all").Alias("a").Type(OptionTypeBool),
+		NewOption("offset").Type(OptionTypeFloat),
+		NewOption("no-cache").Flag(),
+	)
+
+	tests := []struct {
+		name           string
+		args           []string
+		wantOptions    []rawOption
+		wantPositional []string
+	}{
+		{
+			name:        "Long option with equal sign",
+			args:        []string{"--name=value"},
+			wantOptions: []rawOption{{"name", "value"}},
+		},
+		{
+			name:        "Long option with space separated value",
+			args:        []string{"--name", "value"},
+			wantOptions: []rawOption{{"name", "value"}},
+		},
+		{
+			name:        "Long alias",
+			args:        []string{"--nm", "value"},
+			wantOptions: []rawOption{{"name", "value"}},
+		},
+		{
+			name:        "Short alias with space separated value",
+			args:        []string{"-n", "value"},
+			wantOptions: []rawOption{{"name", "value"}},
+		},
+		{
+			name:        "Short alias with attached value",
+			args:        []string{"-p8080"},
+			wantOptions: []rawOption{{"port", "8080"}},
+		},
+		{
+			name:        "Short alias with equal sign",
+			args:        []string{"-p=8080"},
+			wantOptions: []rawOption{{"port", "8080"}},
+		},
+		{
+			name:           "Short alias with equal sign and empty value",
+			args:           []string{"-n=", "pos"},
+			wantOptions:    []rawOption{{"name", ""}},
+			wantPositional: []string{"pos"},
+		},
+		{
+			name:        "Grouped short flags",
+			args:        []string{"-va"},
+			wantOptions: []rawOption{{"verbose", "true"}, {"all", "true"}},
+		},
+		{
+			name:        "Grouped short flags ending with an option taking a value",
+			args:        []string{"-vap", "8080"},
+			wantOptions: []rawOption{{"verbose", "true"}, {"all", "true"}, {"port", "8080"}},
+		},
+		{
+			name:        "Grouped short flags with attached value",
+			args:        []string{"-vp8080"},
+			wantOptions: []rawOption{{"verbose", "true"}, {"port", "8080"}},
+		},
+		{
+			name:           "Flag does not consume next argument",
+			args:           []string{"--verbose", "file.txt"},
+			wantOptions:    []rawOption{{"verbose", "true"}},
+			wantPositional: []string{"file.txt"},
+		},
+		{
+			name:        "Negated flag",
+			args:        []string{"--no-verbose"},
+			wantOptions: []rawOption{{"verbose", "false"}},
+		},
+		{
+			name:        "Flag with explicit value",
+			args:        []string{"--verbose=false"},
+			wantOptions: []rawOption{{"verbose", "false"}},
+		},
+		{
+			name:        "Negation of options which are not flags is unknown",
+			args:        []string{"--no-name"},
+			wantOptions: []rawOption{{"no-name", ""}},
+		},
+		{
+			name:        "Flags named no-... are not negated again",
+			args:        []string{"--no-no-cache", "--no-cache"},
+			wantOptions: []rawOption{{"no-no-cache", ""}, {"no-cache", "true"}},
+		},
+		{
+			name:        "Negative number as separate value",
+			args:        []string{"--offset", "-1.5"},
+			wantOptions: []rawOption{{"offset", "-1.5"}},
+		},
+		{
+			name:        "Value starting with dashes using equal sign",
+			args:        []string{"--name=--weird-value"},
+			wantOptions: []rawOption{{"name", "--weird-value"}},
+		},
+		{
+			name:        "Option followed by another option has empty value",
+			args:        []string{"--name", "--verbose"},
+			wantOptions: []rawOption{{"name", ""}, {"verbose", "true"}},
+		},
+		{
+			name:        "Lone dash is consumed as value",
+			args:        []string{"--name", "-"},
+			wantOptions: []rawOption{{"name", "-"}},
+		},
+		{
+			name:           "Unknown options do not consume next argument",
+			args:           []string{"--unknown", "value", "-x"},
+			wantOptions:    []rawOption{{"unknown", ""}, {"x", ""}},
+			wantPositional: []string{"value"},
+		},
+		{
+			name:           "Double dash ends options",
+			args:           []string{"--verbose", "--", "--name=value", "-v"},
+			wantOptions:    []rawOption{{"verbose", "true"}},
+			wantPositional: []string{"--name=value", "-v"},
+		},
+		{
+			name:           "Positional arguments",
+			args:           []string{"first", "-5", "-", "--name=value", "second"},
+			wantOptions:    []rawOption{{"name", "value"}},
+			wantPositional: []string{"first", "-5", "-", "second"},
+		},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				parsed := parseArgs(scenario.args, definitions)
+				s.Equal(scenario.wantOptions, parsed.options, "parseArgs() options")
+				s.Equal(scenario.wantPositional, parsed.positional, "parseArgs() positional")
+			},
+		)
+	}
+}
+
+func (s *ParserSuite) TestItCanDetectNumbers() {
+	s.True(isNumber("-5"))
+	s.True(isNumber("-.5"))
+	s.True(isNumber("10"))
+	s.False(isNumber("-Inf"))
+	s.False(isNumber("-NaN"))
+	s.False(isNumber("-"))
+	s.False(isNumber("-v"))
+}
+
+func (s *ParserSuite) TestItRejectsOptionsWithoutName() {
+	definitions := MustInputOptionDefinitionMap(NewOption("name").Alias("n"))
+
+	parsed := parseArgs([]string{"--=x", "-=y", "--", "-n", "a"}, definitions)
+
+	s.Len(parsed.errs, 2)
+	s.EqualError(parsed.errs[0], "option name is missing in '--=x'")
+	s.EqualError(parsed.errs[1], "option name is missing in '-=y'")
+	s.Empty(parsed.options)
+	s.Equal([]string{"-n", "a"}, parsed.positional)
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// maxPromptAttempts is how many times the user is asked for a valid value of a missing
+// required option before giving up
+const maxPromptAttempts = 3
+
+// ErrNonInteractive is returned by the Prompter when the user cannot be asked for input,
+// because stdin is not a terminal or the --no-interaction flag was used
+var ErrNonInteractive = errors.New("cannot prompt for input in non-interactive mode")
+
+// Prompter asks the user for input. Questions are written to the output (stderr when used by
+// Bootstrap) and answers are read from the input, one line each. Commands get it through
+// Invocation.Prompter.
+type Prompter struct {
+	input       io.Reader
+	reader      *bufio.Reader
+	output      io.Writer
+	interactive bool
+	assumeYes   bool
+}
+
+// NewPrompter creates a prompter reading the answers from input and writing the questions to
+// output. When interactive is false, questions fail with ErrNonInteractive. When assumeYes is
+// true, confirmations are accepted without asking.
+func NewPrompter(input io.Reader, output io.Writer, interactive bool, assumeYes bool) *Prompter {
+	return &Prompter{
+		input:       input,
+		reader:      bufio.NewReader(input),
+		output:      output,
+		interactive: interactive,
+		assumeYes:   assumeYes,
+	}
+}
+
+// Interactive reports if the user can be asked for input
+func (prompter *Prompter) Interactive() bool {
+	return prompter.interactive
+}
+
+// Ask writes the question and returns the trimmed answer
+func (prompter *Prompter) Ask(question string) (string, error) {
+	if !prompter.interactive {
+		return "", ErrNonInteractive
+	}
+
+	_, _ = fmt.Fprint(prompter.output, question)
+	answer, err := prompter.reader.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || answer == "") {
+		return "", fmt.Errorf("failed to read the answer: %w", err)
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+// AskSecret works like Ask, but the answer is not echoed when the input is a terminal
+func (prompter *Prompter) AskSecret(question string) (string, error) {
+	file, isFile := prompter.input.(*os.File)
+	if !prompter.interactive || !isFile || !term.IsTerminal(int(file.Fd())) {
+		return prompter.Ask(question)
+	}
+
+	_, _ = fmt.Fprint(prompter.output, question)
+	answer, err := term.ReadPassword(int(file.Fd()))
+	_, _ = fmt.Fprintln(prompter.output)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the answer: %w", err)
+	}
+	return strings.TrimSpace(string(answer)), nil
+}
+
+// Confirm asks a yes/no question, like "Really drop table? [y/N]", where anything other than
+// "y" or "yes" means no. It returns true without asking when the --yes flag was used.
+func (prompter *Prompter) Confirm(question string) (bool, error) {
+	if prompter.assumeYes {
+		return true, nil
+	}
+
+	answer, err := prompter.Ask(question + " [y/N] ")
+	if err != nil {
+		if errors.Is(err, ErrNonInteractive) {
+			return false, fmt.Errorf("%w, use --yes to confirm", err)
+		}
+		return false, err
+	}
+	return slices.Contains([]string{"y", "yes"}, strings.ToLower(answer)), nil
+}
+
+// promptMissingOptions asks the user for the required options which were not provided on the
+// command line nor through their environment variable. Invalid or empty answers are asked
+// again, up to maxPromptAttempts times.
+func promptMissingOptions(
+	prompter *Prompter,
+	provided []rawOption,
+	definitions InputOptionDefinitionMap,
+) ([]rawOption, error) {
+	var prompted []rawOption
+	for _, def := range sortedOptionDefinitions(definitions) {
+		if !def.required || slices.ContainsFunc(
+			provided, func(option rawOption) bool {
+				return option.name == def.name && option.value != ""
+			},
+		) {
+			continue
+		}
+		if envVal, _ := os.LookupEnv(def.envVar); def.envVar != "" &&
+			strings.TrimSpace(envVal) != "" {
+			continue
+		}
+
+		value, err := promptOption(prompter, def)
+		if err != nil {
+			return prompted, err
+		}
+		prompted = append(prompted, rawOption{name: def.name, value: value})
+	}
+	return prompted, nil
+}
+
+// promptOption asks for the option value until a valid one is provided
+func promptOption(prompter *Prompter, def InputOptionDefinition) (string, error) {
+	question := "Value for --" + def.name
+	if def.description != "" {
+		question += " (" + def.description + ")"
+	}
+	question += ": "
+
+	for range maxPromptAttempts {
+		ask := prompter.Ask
+		if def.sensitive {
+			ask = prompter.AskSecret
+		}
+		value, err := ask(question)
+		if err != nil {
+			return "", err
+		}
+
+		if value == "" {
+			_, _ = fmt.Fprintf(prompter.output, "option '%s' is required\n", def.name)
+			continue
+		}
+		if err = def.validateValues(def.splitValue(value)); err != nil {
+			_, _ = fmt.Fprintln(prompter.output, err.Error())
+			continue
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf("no valid value provided for option '%s'", def.name)
+}
+
+// isTerminal checks if the reader is a terminal, so the user can be prompted for input
+func isTerminal(reader io.Reader) bool {
+	file, isFile := reader.(*os.File)
+	return isFile && term.IsTerminal(int(file.Fd()))
+}
@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"github.com/rsgcata/gocommon/params"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"strings"
+	"testing"
+)
+
+type PromptSuite struct {
+	suite.Suite
+}
+
+func TestPromptSuite(t *testing.T) {
+	suite.Run(t, new(PromptSuite))
+}
+
+func (s *PromptSuite) TestPrompterCanAskQuestions() {
+	var output bytes.Buffer
+	prompter := NewPrompter(strings.NewReader(" alice \nsecret\nlast"), &output, true, false)
+
+	answer, err := prompter.Ask("Name: ")
+	s.NoError(err)
+	s.Equal("alice", answer)
+
+	answer, err = prompter.AskSecret("Password: ")
+	s.NoError(err)
+	s.Equal("secret", answer)
+
+	answer, err = prompter.Ask("Last: ")
+	s.NoError(err)
+	s.Equal("last", answer, "the answer can end without a new line")
+
+	_, err = prompter.Ask("Again: ")
+	s.ErrorIs(err, io.EOF)
+	s.Equal("Name: Password: Last: Again: ", output.String())
+	s.True(prompter.Interactive())
+}
+
+func (s *PromptSuite) TestPrompterCanAskConfirmations() {
+	tests := []struct {
+		name        string
+		input       string
+		interactive bool
+		assumeYes   bool
+		want        bool
+		wantErr     error
+	}{
+		{"Yes", "y\n", true, false, true, nil},
+		{"Long yes", "YES\n", true, false, true, nil},
+		{"No", "n\n", true, false, false, nil},
+		{"Empty answer", "\n", true, false, false, nil},
+		{"Assume yes", "", false, true, true, nil},
+		{"Non-interactive", "y\n", false, false, false, ErrNonInteractive},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				var output bytes.Buffer
+				prompter := NewPrompter(
+					strings.NewReader(scenario.input),
+					&output,
+					scenario.interactive,
+					scenario.assumeYes,
+				)
+
+				confirmed, err := prompter.Confirm("Really drop table?")
+				s.Equal(scenario.want, confirmed)
+				if scenario.wantErr != nil {
+					s.ErrorIs(err, scenario.wantErr)
+					s.Contains(err.Error(), "--yes")
+				} else {
+					s.NoError(err)
+				}
+				if scenario.interactive && !scenario.assumeYes {
+					s.Equal("Really drop table? [y/N] ", output.String())
+				}
+			},
+		)
+	}
+}
+
+func (s *PromptSuite) bootstrap(
+	args []string,
+	cmd Command,
+	stdin string,
+	interactive bool,
+) (stderr string, exitCode int) {
+	registry := NewCommandsRegistry()
+	_ = registry.Register(cmd)
+
+	var stderrBuf bytes.Buffer
+	BootstrapWithOptions(
+		args,
+		*registry,
+		BootstrapOptions{
+			IO: IO{
+				Stdin:  strings.NewReader(stdin),
+				Stdout: &bytes.Buffer{},
+				Stderr: &stderrBuf,
+			},
+			ProcessExit:   func(code int) { exitCode = code },
+			IsInteractive: func(io.Reader) bool { return interactive },
+		},
+	)
+	return stderrBuf.String(), exitCode
+}
+
+func (s *PromptSuite) TestBootstrapPromptsForMissingRequiredOptions() {
+	var gotOptions InputOptionsMap
+	cmd := &bootstrapMockCommand{
+		id: "connect",
+		inputDef: MustInputOptionDefinitionMap(
+			NewOption("port").Description("Server port").Type(OptionTypeInt).Required(),
+			NewOption("password").Sensitive().Required(),
+			NewOption("user").Required(),
+		),
+		execFunc: func(options InputOptionsMap, _ io.Writer) error {
+			gotOptions = options
+			return nil
+		},
+	}
+
+	stderr, exitCode := s.bootstrap(
+		[]string{"connect", "--user=admin"},
+		cmd,
+		"\nsecret\nabc\n8080\n",
+		true,
+	)
+
+	s.Equal(StatusOk, exitCode)
+	s.Equal(params.RawVal("8080"), gotOptions["port"].RawVal())
+	s.Equal(ValueSourcePrompt, gotOptions["port"].Source())
+	s.Equal(params.RawVal("secret"), gotOptions["password"].RawVal())
+	s.Equal(ValueSourcePrompt, gotOptions["password"].Source())
+	s.Equal(ValueSourceUser, gotOptions["user"].Source())
+	s.Equal(
+		"Value for --password: option 'password' is required\n"+
+			"Value for --password: "+
+			"Value for --port (Server port): option 'port' expects an integer value, got 'abc'\n"+
+			"Value for --port (Server port): ",
+		stderr,
+	)
+}
+
+func (s *PromptSuite) TestBootstrapDoesNotPromptInNonInteractiveMode() {
+	executed := false
+	cmd := &bootstrapMockCommand{
+		id:       "connect",
+		inputDef: MustInputOptionDefinitionMap(NewOption("user").Required()),
+		execFunc: func(_ InputOptionsMap, _ io.Writer) error {
+			executed = true
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		interactive bool
+	}{
+		{"Stdin is not a terminal", []string{"connect"}, false},
+		{"No interaction flag", []string{"connect", "--no-interaction"}, true},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				stderr, exitCode := s.bootstrap(scenario.args, cmd, "admin\n", scenario.interactive)
+
+				s.Equal(StatusUsage, exitCode)
+				s.False(executed)
+				s.Contains(stderr, "option 'user' is required")
+				s.NotContains(stderr, "Value for --user")
+			},
+		)
+	}
+
+	stderr, exitCode := s.bootstrap([]string{"connect"}, cmd, "", true)
+	s.Equal(StatusUsage, exitCode)
+	s.Contains(stderr, "failed to read the answer")
+}
+
+func (s *PromptSuite) TestCommandsCanAskConfirmations() {
+	var confirmed bool
+	var confirmErr error
+	cmd := &contextMockCommand{
+		bootstrapMockCommand: bootstrapMockCommand{
+			id:       "drop",
+			inputDef: InputOptionDefinitionMap{},
+		},
+		execContextFunc: func(_ context.Context, invocation Invocation) error {
+			confirmed, confirmErr = invocation.Prompter.Confirm("Really drop table?")
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		stdin         string
+		interactive   bool
+		wantConfirmed bool
+		wantErr       bool
+	}{
+		{"Confirmed by user", []string{"drop"}, "y\n", true, true, false},
+		{"Refused by user", []string{"drop"}, "n\n", true, false, false},
+		{"Confirmed by yes flag", []string{"drop", "--yes"}, "", false, true, false},
+		{"Non-interactive", []string{"drop"}, "y\n", false, false, true},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				_, exitCode := s.bootstrap(scenario.args, cmd, scenario.stdin, scenario.interactive)
+
+				s.Equal(StatusOk, exitCode)
+				s.Equal(scenario.wantConfirmed, confirmed)
+				s.Equal(scenario.wantErr, confirmErr != nil)
+			},
+		)
+	}
+}
+
+func (s *PromptSuite) TestGlobalFlagsAreLeftToCommandsDeclaringThem() {
+	var gotOptions InputOptionsMap
+	cmd := &bootstrapMockCommand{
+		id:       "deploy",
+		inputDef: MustInputOptionDefinitionMap(NewOption("yes").Flag()),
+		execFunc: func(options InputOptionsMap, _ io.Writer) error {
+			gotOptions = options
+			return nil
+		},
+	}
+
+	_, exitCode := s.bootstrap([]string{"deploy", "--yes"}, cmd, "", false)
+
+	s.Equal(StatusOk, exitCode)
+	s.Equal(params.RawVal("true"), gotOptions["yes"].RawVal())
+}
@@ -0,0 +1,291 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// CommandsRegistry holds the commands available to Bootstrap. Command ids can be
+// hierarchical, with the path segments separated by spaces ("db migrate up"). Every prefix of
+// such a path ("db", "db migrate") is a group, which can be given a description with
+// RegisterGroup.
+type CommandsRegistry struct {
+	commands    map[string]Command
+	groups      map[string]string
+	aliases     map[string]string
+	middlewares []CommandMiddleware
+}
+
+// AliasedCommand is implemented by commands which can also be run using other names. Aliases
+// are full command paths, like the command id.
+type AliasedCommand interface {
+	Command
+	Aliases() []string
+}
+
+// HiddenCommand is implemented by commands which can be run but are not listed by the help,
+// the command suggestions or the shell completion, like internal or deprecated commands
+type HiddenCommand interface {
+	Command
+	Hidden() bool
+}
+
+// isHidden checks if the command should be left out of the help and the suggestions
+func isHidden(cmd Command) bool {
+	hiddenCmd, ok := cmd.(HiddenCommand)
+	return ok && hiddenCmd.Hidden()
+}
+
+func NewCommandsRegistry() *CommandsRegistry {
+	return &CommandsRegistry{
+		commands: map[string]Command{},
+		groups:   map[string]string{},
+		aliases:  map[string]string{},
+	}
+}
+
+func (registry *CommandsRegistry) Register(cmd Command) error {
+	path, err := normalizeCommandPath(cmd.Id())
+	if err != nil {
+		return err
+	}
+
+	if registry.pathTaken(path) {
+		return fmt.Errorf("command '%s' is already registered", cmd.Id())
+	}
+
+	var aliases []string
+	if aliasedCmd, ok := cmd.(AliasedCommand); ok {
+		for _, alias := range aliasedCmd.Aliases() {
+			normalizedAlias, err := normalizeCommandPath(alias)
+			if err != nil {
+				return err
+			}
+			if registry.pathTaken(normalizedAlias) || normalizedAlias == path ||
+				slices.Contains(aliases, normalizedAlias) {
+				return fmt.Errorf(
+					"alias '%s' of command '%s' is already registered",
+					alias,
+					cmd.Id(),
+				)
+			}
+			aliases = append(aliases, normalizedAlias)
+		}
+	}
+
+	if registry.commands == nil {
+		registry.commands = map[string]Command{}
+	}
+	if registry.aliases == nil {
+		registry.aliases = map[string]string{}
+	}
+	registry.commands[path] = cmd
+	for _, alias := range aliases {
+		registry.aliases[alias] = path
+	}
+	return nil
+}
+
+// Use adds middlewares wrapping the execution of every command of the registry. They run in
+// the order they are added.
+func (registry *CommandsRegistry) Use(middlewares ...CommandMiddleware) {
+	registry.middlewares = append(registry.middlewares, middlewares...)
+}
+
+// pathTaken reports if the path is already used by a command or an alias
+func (registry *CommandsRegistry) pathTaken(path string) bool {
+	_, isCommand := registry.commands[path]
+	_, isAlias := registry.aliases[path]
+	return isCommand || isAlias
+}
+
+// RegisterGroup adds a description for a group of commands, like "db" or "db migrate". Groups
+// are listed separately in help output.
+func (registry *CommandsRegistry) RegisterGroup(path string, description string) error {
+	normalizedPath, err := normalizeCommandPath(path)
+	if err != nil {
+		return err
+	}
+
+	if _, exists := registry.groups[normalizedPath]; exists {
+		return fmt.Errorf("group '%s' is already registered", path)
+	}
+
+	if registry.groups == nil {
+		registry.groups = map[string]string{}
+	}
+	registry.groups[normalizedPath] = description
+	return nil
+}
+
+func (registry *CommandsRegistry) Commands() map[string]Command {
+	cmdCopy := make(map[string]Command, len(registry.commands))
+	for name, cmd := range registry.commands {
+		cmdCopy[name] = cmd
+	}
+	return cmdCopy
+}
+
+// Groups returns all the command groups, keyed by path, with their descriptions. Groups
+// which were not registered explicitly have an empty description.
+func (registry *CommandsRegistry) Groups() map[string]string {
+	groups := make(map[string]string, len(registry.groups))
+	for path := range registry.commands {
+		for group := commandGroup(path); group != ""; group = commandGroup(group) {
+			groups[group] = ""
+		}
+	}
+	for path, description := range registry.groups {
+		groups[path] = description
+	}
+	return groups
+}
+
+// Command returns the command registered with the provided id or alias
+func (registry *CommandsRegistry) Command(id string) (Command, bool) {
+	path := strings.Join(strings.Fields(id), " ")
+	if aliasedPath, isAlias := registry.aliases[path]; isAlias {
+		path = aliasedPath
+	}
+	cmd, ok := registry.commands[path]
+	return cmd, ok
+}
+
+// Aliases returns all the command aliases, mapped to the ids of the commands they point to
+func (registry *CommandsRegistry) Aliases() map[string]string {
+	return maps.Clone(registry.aliases)
+}
+
+// resolve finds the longest command path at the beginning of the provided words. When no
+// command matches, it looks for the longest group path instead. Returns the matched path,
+// the number of consumed words and if the path is a command or a group. Only the words
+// before the first option are considered.
+func (registry *CommandsRegistry) resolve(
+	words []string,
+) (path string, consumed int, isCommand bool, isGroup bool) {
+	candidates := 0
+	for candidates < len(words) && !strings.HasPrefix(words[candidates], "-") {
+		candidates++
+	}
+
+	for i := candidates; i > 0; i-- {
+		path = strings.Join(words[:i], " ")
+		if registry.pathTaken(path) {
+			return path, i, true, false
+		}
+	}
+
+	groups := registry.Groups()
+	for i := candidates; i > 0; i-- {
+		path = strings.Join(words[:i], " ")
+		if _, exists := groups[path]; exists {
+			return path, i, false, true
+		}
+	}
+
+	if len(words) > 0 {
+		return words[0], 0, false, false
+	}
+	return "", 0, false, false
+}
+
+// paths returns all the command ids and aliases
+func (registry *CommandsRegistry) paths() []string {
+	paths := slices.Collect(maps.Keys(registry.commands))
+	return append(paths, slices.Collect(maps.Keys(registry.aliases))...)
+}
+
+// visiblePaths returns the command ids and aliases of the commands which are not hidden
+func (registry *CommandsRegistry) visiblePaths() []string {
+	var paths []string
+	for _, path := range registry.paths() {
+		if cmd, _ := registry.Command(path); !isHidden(cmd) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// expandPrefixes replaces the leading words which are a unique prefix of a command path
+// segment with the full segment, so "mig up" can be used for "migrate up". Words which match
+// a segment exactly are kept, expansion stops at the first word which cannot be expanded.
+func (registry *CommandsRegistry) expandPrefixes(words []string) []string {
+	paths := registry.paths()
+	expanded := slices.Clone(words)
+	for i, word := range expanded {
+		if strings.HasPrefix(word, "-") {
+			break
+		}
+
+		matched := strings.Join(expanded[:i], " ")
+		segments := map[string]bool{}
+		for _, path := range paths {
+			pathWords := strings.Fields(path)
+			if len(pathWords) > i && strings.Join(pathWords[:i], " ") == matched {
+				segments[pathWords[i]] = true
+			}
+		}
+
+		if segments[word] {
+			continue
+		}
+
+		var candidates []string
+		for segment := range segments {
+			if strings.HasPrefix(segment, word) {
+				candidates = append(candidates, segment)
+			}
+		}
+		if len(candidates) != 1 {
+			break
+		}
+		expanded[i] = candidates[0]
+	}
+	return expanded
+}
+
+// suggestCommands returns the command ids, aliases and groups closest to the unknown path.
+// Candidates are compared using only as many words as the unknown path has.
+func (registry *CommandsRegistry) suggestCommands(unknownPath string) []string {
+	wordsCount := len(strings.Fields(unknownPath))
+	var candidates []string
+	for _, path := range append(
+		registry.visiblePaths(),
+		slices.Collect(maps.Keys(registry.groups))...,
+	) {
+		pathWords := strings.Fields(path)
+		candidates = append(
+			candidates,
+			strings.Join(pathWords[:min(wordsCount, len(pathWords))], " "),
+		)
+	}
+	return suggest(unknownPath, candidates)
+}
+
+// normalizeCommandPath collapses the whitespace between the path segments and validates them
+func normalizeCommandPath(path string) (string, error) {
+	segments := strings.Fields(path)
+	if len(segments) == 0 {
+		return "", fmt.Errorf("command id '%s' is invalid, it cannot be empty", path)
+	}
+
+	for _, segment := range segments {
+		if strings.HasPrefix(segment, "-") {
+			return "", fmt.Errorf(
+				"command id '%s' is invalid, its words cannot start with '-'",
+				path,
+			)
+		}
+	}
+	return strings.Join(segments, " "), nil
+}
+
+// commandGroup returns the group a command path belongs to, "" for top level commands
+func commandGroup(path string) string {
+	if index := strings.LastIndex(path, " "); index >= 0 {
+		return path[:index]
+	}
+	return ""
+}
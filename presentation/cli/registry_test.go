@@ -0,0 +1,333 @@
+package cli
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"testing"
+)
+
+type RegistrySuite struct {
+	suite.Suite
+}
+
+func TestRegistrySuite(t *testing.T) {
+	suite.Run(t, new(RegistrySuite))
+}
+
+func (s *RegistrySuite) TestItCanRegisterHierarchicalCommands() {
+	registry := NewCommandsRegistry()
+
+	s.NoError(registry.Register(&bootstrapMockCommand{id: "db  migrate up"}))
+	s.NoError(registry.Register(&bootstrapMockCommand{id: "db migrate down"}))
+	s.Error(registry.Register(&bootstrapMockCommand{id: "db migrate  up"}))
+	s.Error(registry.Register(&bootstrapMockCommand{id: "  "}))
+	s.Error(registry.Register(&bootstrapMockCommand{id: "db --up"}))
+
+	cmd, exists := registry.Command("db migrate up")
+	s.True(exists)
+	s.Equal("db  migrate up", cmd.Id())
+
+	s.NoError(registry.RegisterGroup("db", "Database commands"))
+	s.Error(registry.RegisterGroup("db", "Database commands"))
+	s.Equal(
+		map[string]string{"db": "Database commands", "db migrate": ""},
+		registry.Groups(),
+	)
+}
+
+func (s *RegistrySuite) TestZeroValueRegistryIsUsable() {
+	registry := CommandsRegistry{}
+
+	s.NoError(registry.Register(&bootstrapMockCommand{id: "test"}))
+	s.NoError(registry.RegisterGroup("db", "Database commands"))
+	s.Len(registry.Commands(), 1)
+}
+
+func (s *RegistrySuite) TestItCanResolveLongestCommandPath() {
+	registry := NewCommandsRegistry()
+	_ = registry.Register(&bootstrapMockCommand{id: "db"})
+	_ = registry.Register(&bootstrapMockCommand{id: "db migrate up"})
+	_ = registry.Register(&bootstrapMockCommand{id: "cache clear"})
+
+	tests := []struct {
+		name          string
+		words         []string
+		wantPath      string
+		wantConsumed  int
+		wantIsCommand bool
+		wantIsGroup   bool
+	}{
+		{"Nested command", []string{"db", "migrate", "up", "x"}, "db migrate up", 3, true, false},
+		{"Parent command", []string{"db", "status"}, "db", 1, true, false},
+		{"Stops at options", []string{"db", "--migrate", "up"}, "db", 1, true, false},
+		{"Group only", []string{"cache"}, "cache", 1, false, true},
+		{"Nested group", []string{"db", "migrate", "--x"}, "db", 1, true, false},
+		{"Unknown", []string{"unknown", "cmd"}, "unknown", 0, false, false},
+		{"Empty", []string{}, "", 0, false, false},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				path, consumed, isCommand, isGroup := registry.resolve(scenario.words)
+				s.Equal(scenario.wantPath, path)
+				s.Equal(scenario.wantConsumed, consumed)
+				s.Equal(scenario.wantIsCommand, isCommand)
+				s.Equal(scenario.wantIsGroup, isGroup)
+			},
+		)
+	}
+}
+
+func (s *RegistrySuite) TestBootstrapCanRunNestedCommands() {
+	registry := NewCommandsRegistry()
+	var gotOptions InputOptionsMap
+	_ = registry.Register(
+		&bootstrapMockCommand{
+			id:       "db migrate up",
+			inputDef: MustInputOptionDefinitionMap(NewOption("steps").Type(OptionTypeInt)),
+			execFunc: func(options InputOptionsMap, writer io.Writer) error {
+				gotOptions = options
+				_, _ = writer.Write([]byte("migrated"))
+				return nil
+			},
+		},
+	)
+	_ = registry.RegisterGroup("db", "Database commands")
+
+	var exitCode int
+	var buf bytes.Buffer
+	Bootstrap(
+		[]string{"db", "migrate", "up", "--steps=2"},
+		*registry,
+		&buf,
+		func(code int) { exitCode = code },
+	)
+
+	s.Equal(StatusOk, exitCode)
+	s.Equal("migrated", buf.String())
+	s.Equal("2", string(gotOptions["steps"].RawVal()))
+
+	buf.Reset()
+	Bootstrap([]string{"db"}, *registry, &buf, func(code int) { exitCode = code })
+
+	s.Equal(StatusOk, exitCode)
+	s.Contains(buf.String(), "Available CLI Commands in group db")
+	s.Contains(buf.String(), "Database commands")
+	s.Contains(buf.String(), "db migrate up")
+}
+
+func (s *RegistrySuite) TestHelpListsCommandsByGroup() {
+	cmd := &HelpCommand{
+		availableCommands: []Command{
+			&mockCommand{id: "db migrate up", description: "Run migrations"},
+			&mockCommand{id: "cache clear", description: "Clear cache"},
+			&mockCommand{id: "version", description: "Show version"},
+			&mockCommand{id: "about", description: "About"},
+		},
+		groups: map[string]string{"db": "Database commands", "db migrate": "", "cache": ""},
+	}
+
+	var buf bytes.Buffer
+	s.NoError(cmd.Exec(InputOptionsMap{}, &buf))
+	output := buf.String()
+
+	order := []string{"about", "version", "cache", "cache clear", "db", "Database commands",
+		"db migrate", "db migrate up"}
+	lastIndex := -1
+	for _, text := range order {
+		index := bytes.Index(buf.Bytes()[lastIndex+1:], []byte(text))
+		s.GreaterOrEqual(index, 0, "help output should contain %q after previous entries", text)
+		lastIndex += index + 1
+	}
+	s.Contains(output, "=========")
+
+	cmd.group = "db"
+	buf.Reset()
+	s.NoError(cmd.Exec(InputOptionsMap{}, &buf))
+	s.Contains(buf.String(), "db migrate up")
+	s.NotContains(buf.String(), "cache clear")
+	s.NotContains(buf.String(), "version")
+}
+
+// Mock command which can also be run using aliases
+type aliasedMockCommand struct {
+	bootstrapMockCommand
+	aliases []string
+}
+
+func (m *aliasedMockCommand) Aliases() []string {
+	return m.aliases
+}
+
+func (s *RegistrySuite) TestItCanRegisterCommandAliases() {
+	registry := NewCommandsRegistry()
+	migrate := &aliasedMockCommand{
+		bootstrapMockCommand: bootstrapMockCommand{id: "db migrate"},
+		aliases:              []string{"migrate", "db  mig"},
+	}
+
+	s.NoError(registry.Register(migrate))
+	s.Error(registry.Register(&bootstrapMockCommand{id: "migrate"}))
+	s.Error(
+		registry.Register(
+			&aliasedMockCommand{
+				bootstrapMockCommand: bootstrapMockCommand{id: "db seed"},
+				aliases:              []string{"db migrate"},
+			},
+		),
+	)
+	s.Error(
+		registry.Register(
+			&aliasedMockCommand{
+				bootstrapMockCommand: bootstrapMockCommand{id: "cache"},
+				aliases:              []string{"cache"},
+			},
+		),
+	)
+
+	cmd, exists := registry.Command("db mig")
+	s.True(exists)
+	s.Same(migrate, cmd)
+	s.Equal(map[string]string{"migrate": "db migrate", "db mig": "db migrate"}, registry.Aliases())
+
+	path, consumed, isCommand, _ := registry.resolve([]string{"migrate", "--x"})
+	s.Equal("migrate", path)
+	s.Equal(1, consumed)
+	s.True(isCommand)
+}
+
+func (s *RegistrySuite) TestItCanExpandUniquePrefixes() {
+	registry := NewCommandsRegistry()
+	_ = registry.Register(&bootstrapMockCommand{id: "migrate up"})
+	_ = registry.Register(&bootstrapMockCommand{id: "migrate down"})
+	_ = registry.Register(&bootstrapMockCommand{id: "make"})
+	_ = registry.Register(&bootstrapMockCommand{id: "cache clear"})
+
+	tests := []struct {
+		name  string
+		words []string
+		want  []string
+	}{
+		{"Unique prefix", []string{"mig", "up"}, []string{"migrate", "up"}},
+		{"Nested prefixes", []string{"mig", "d", "x"}, []string{"migrate", "down", "x"}},
+		{"Ambiguous prefix", []string{"m", "up"}, []string{"m", "up"}},
+		{"Exact match kept", []string{"make", "up"}, []string{"make", "up"}},
+		{"Stops at options", []string{"c", "--c"}, []string{"cache", "--c"}},
+		{"Unknown", []string{"x", "cl"}, []string{"x", "cl"}},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				s.Equal(scenario.want, registry.expandPrefixes(scenario.words))
+			},
+		)
+	}
+}
+
+func (s *RegistrySuite) TestBootstrapSuggestsSimilarCommands() {
+	registry := NewCommandsRegistry()
+	_ = registry.Register(
+		&aliasedMockCommand{
+			bootstrapMockCommand: bootstrapMockCommand{id: "migrate"},
+			aliases:              []string{"upgrade"},
+		},
+	)
+	_ = registry.Register(&bootstrapMockCommand{id: "db seed"})
+	_ = registry.Register(&bootstrapMockCommand{id: "cache clear"})
+
+	tests := []struct {
+		name            string
+		args            []string
+		wantSuggestions []string
+		notSuggested    []string
+	}{
+		{"Typo in command", []string{"migrat"}, []string{"migrate"}, []string{"cache"}},
+		{"Typo in alias", []string{"upgrad"}, []string{"upgrade"}, []string{"migrate"}},
+		{"Typo in group", []string{"dv", "seed"}, []string{"db"}, []string{"cache"}},
+		{"Unknown in group", []string{"db", "sed"}, []string{"db seed"}, []string{"cache"}},
+		{"Nothing similar", []string{"zzzzzzzz"}, nil, []string{"migrate", "Did you mean"}},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				var exitCode int
+				var stderr bytes.Buffer
+				BootstrapWithOptions(
+					scenario.args,
+					*registry,
+					BootstrapOptions{
+						IO:          IO{Stdout: &bytes.Buffer{}, Stderr: &stderr},
+						ProcessExit: func(code int) { exitCode = code },
+					},
+				)
+
+				s.Equal(StatusUsage, exitCode)
+				s.Contains(stderr.String(), "does not exist")
+				s.Contains(stderr.String(), "Run 'help' to see all available commands")
+				for _, suggestion := range scenario.wantSuggestions {
+					s.Contains(stderr.String(), "Did you mean one of these?\n\t"+suggestion)
+				}
+				for _, text := range scenario.notSuggested {
+					s.NotContains(stderr.String(), text)
+				}
+			},
+		)
+	}
+}
+
+func (s *RegistrySuite) TestBootstrapCanMatchCommandPrefixesWhenEnabled() {
+	registry := NewCommandsRegistry()
+	executed := false
+	_ = registry.Register(
+		&bootstrapMockCommand{
+			id: "db migrate",
+			execFunc: func(_ InputOptionsMap, _ io.Writer) error {
+				executed = true
+				return nil
+			},
+		},
+	)
+
+	var exitCode int
+	BootstrapWithOptions(
+		[]string{"d", "mig"},
+		*registry,
+		BootstrapOptions{
+			IO:          IO{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}},
+			ProcessExit: func(code int) { exitCode = code },
+		},
+	)
+	s.Equal(StatusUsage, exitCode)
+	s.False(executed)
+
+	BootstrapWithOptions(
+		[]string{"d", "mig"},
+		*registry,
+		BootstrapOptions{
+			IO:                    IO{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}},
+			ProcessExit:           func(code int) { exitCode = code },
+			CommandPrefixMatching: true,
+		},
+	)
+	s.Equal(StatusOk, exitCode)
+	s.True(executed)
+}
+
+func (s *RegistrySuite) TestHelpShowsCommandAliases() {
+	cmd := &HelpCommand{
+		availableCommands: []Command{
+			&aliasedMockCommand{
+				bootstrapMockCommand: bootstrapMockCommand{id: "migrate"},
+				aliases:              []string{"upgrade", "mig"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	s.NoError(cmd.Exec(InputOptionsMap{}, &buf))
+	s.Contains(buf.String(), "Aliases: upgrade, mig")
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// ANSI escape sequences used by the Styler
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// Styler decorates text written to a terminal with ANSI colors and styles. When it is
+// disabled, and for a nil Styler, the text is returned unchanged. Bootstrap passes a Styler
+// for each output stream to the commands through Invocation, disabled when the stream is not
+// a terminal, when the NO_COLOR environment variable is set or when the --no-color global
+// flag is used.
+type Styler struct {
+	enabled bool
+}
+
+// NewStyler creates a styler, which styles the text only when enabled is true
+func NewStyler(enabled bool) *Styler {
+	return &Styler{enabled: enabled}
+}
+
+// Enabled reports if the text is styled
+func (styler *Styler) Enabled() bool {
+	return styler != nil && styler.enabled
+}
+
+// Heading styles section headings, like "Usage:" in help output
+func (styler *Styler) Heading(text string) string {
+	return styler.apply(ansiBold, text)
+}
+
+// Error styles error messages, in red
+func (styler *Styler) Error(text string) string {
+	return styler.apply(ansiRed, text)
+}
+
+// Warning styles warning messages, in yellow
+func (styler *Styler) Warning(text string) string {
+	return styler.apply(ansiYellow, text)
+}
+
+// Success styles success messages, in green
+func (styler *Styler) Success(text string) string {
+	return styler.apply(ansiGreen, text)
+}
+
+// apply wraps the text with the escape sequence. Trailing new lines are left after the reset
+// sequence, so the style does not leak to the next line.
+func (styler *Styler) apply(sequence string, text string) string {
+	trimmed := strings.TrimRight(text, "\n")
+	if !styler.Enabled() || trimmed == "" {
+		return text
+	}
+	return sequence + trimmed + ansiReset + text[len(trimmed):]
+}
+
+// colorsAllowed checks the NO_COLOR convention (https://no-color.org): colors are disabled
+// when the NO_COLOR environment variable is set to a non-empty value
+func colorsAllowed() bool {
+	return os.Getenv("NO_COLOR") == ""
+}
+
+// isColorTerminal checks if the writer is a terminal, which can display colors
+func isColorTerminal(writer io.Writer) bool {
+	file, isFile := writer.(*os.File)
+	return isFile && isTerminal(file)
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"testing"
+)
+
+type StyleSuite struct {
+	suite.Suite
+}
+
+func TestStyleSuite(t *testing.T) {
+	suite.Run(t, new(StyleSuite))
+}
+
+func (s *StyleSuite) TestStylerStylesTextOnlyWhenEnabled() {
+	styler := NewStyler(true)
+	s.True(styler.Enabled())
+	s.Equal("\x1b[1mUsage:\x1b[0m", styler.Heading("Usage:"))
+	s.Equal("\x1b[31mfailed\x1b[0m\n\n", styler.Error("failed\n\n"))
+	s.Equal("\x1b[33mcareful\x1b[0m", styler.Warning("careful"))
+	s.Equal("\x1b[32mdone\x1b[0m", styler.Success("done"))
+	s.Equal("\n", styler.Success("\n"))
+
+	for _, disabled := range []*Styler{NewStyler(false), nil} {
+		s.False(disabled.Enabled())
+		s.Equal("Usage:", disabled.Heading("Usage:"))
+		s.Equal("failed\n", disabled.Error("failed\n"))
+		s.Equal("careful", disabled.Warning("careful"))
+		s.Equal("done", disabled.Success("done"))
+	}
+}
+
+func (s *StyleSuite) bootstrap(args []string, colorTerminal bool) (stdout, stderr string) {
+	registry := NewCommandsRegistry()
+	_ = registry.Register(
+		&contextMockCommand{
+			bootstrapMockCommand: bootstrapMockCommand{
+				id:       "deploy",
+				inputDef: InputOptionDefinitionMap{},
+			},
+			execContextFunc: func(_ context.Context, invocation Invocation) error {
+				_, _ = io.WriteString(invocation.Stdout, invocation.StdoutStyler.Success("ok"))
+				_, _ = io.WriteString(invocation.Stderr, invocation.StderrStyler.Warning("slow"))
+				return errors.New("failed")
+			},
+		},
+	)
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	BootstrapWithOptions(
+		args,
+		*registry,
+		BootstrapOptions{
+			IO:              IO{Stdout: &stdoutBuf, Stderr: &stderrBuf},
+			ProcessExit:     func(int) {},
+			IsColorTerminal: func(io.Writer) bool { return colorTerminal },
+		},
+	)
+	return stdoutBuf.String(), stderrBuf.String()
+}
+
+func (s *StyleSuite) TestBootstrapStylesOutputOfColorTerminals() {
+	stdout, stderr := s.bootstrap([]string{"deploy"}, true)
+	s.Equal("\x1b[32mok\x1b[0m", stdout)
+	s.Equal(
+		"\x1b[33mslow\x1b[0m\x1b[31mFailed to execute command deploy with error: failed\x1b[0m\n",
+		stderr,
+	)
+
+	stdout, _ = s.bootstrap([]string{"help"}, true)
+	s.Contains(stdout, "\x1b[1mAvailable CLI Commands:\x1b[0m\n")
+	s.Contains(stdout, "\x1b[1mUsage:\x1b[0m deploy\n")
+
+	stdout, _ = s.bootstrap([]string{"deploy", "--help"}, true)
+	s.Contains(stdout, "\x1b[1mUsage:\x1b[0m\n  deploy\n")
+}
+
+func (s *StyleSuite) TestNoColorFlagIsNotNegated() {
+	stdout, stderr := s.bootstrap([]string{"deploy", "--no-no-color"}, false)
+	s.Empty(stdout)
+	s.Contains(stderr, "option 'no-no-color' is not defined")
+
+	stdout, _ = s.bootstrap([]string{"help"}, false)
+	s.Contains(stdout, "  --no-color Disable colored output")
+	s.NotContains(stdout, "--no-no-color")
+}
+
+func (s *StyleSuite) TestBootstrapDisablesColors() {
+	tests := []struct {
+		name          string
+		args          []string
+		colorTerminal bool
+		noColorEnv    string
+	}{
+		{"Not a terminal", []string{"deploy"}, false, ""},
+		{"No color flag", []string{"--no-color", "deploy"}, true, ""},
+		{"No color environment variable", []string{"deploy"}, true, "1"},
+	}
+
+	for _, scenario := range tests {
+		s.Run(
+			scenario.name, func() {
+				s.T().Setenv("NO_COLOR", scenario.noColorEnv)
+
+				stdout, stderr := s.bootstrap(scenario.args, scenario.colorTerminal)
+				s.Equal("ok", stdout)
+				s.NotContains(stderr, "\x1b[")
+				s.Contains(stderr, "slowFailed to execute command deploy")
+			},
+		)
+	}
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+)
+
+// maxSuggestions is the maximum number of "did you mean" suggestions shown to the user
+const maxSuggestions = 3
+
+// suggest returns the candidates closest to the input, by edit distance, best matches first.
+// Candidates are considered similar if their distance is at most a third of the input length
+// (minimum 2) or if they start with the input. Inputs shorter than 3 characters allow one edit
+// less than their length, so a single character never matches every other single character.
+func suggest(input string, candidates []string) []string {
+	type match struct {
+		candidate string
+		distance  int
+	}
+
+	maxDistance := max(2, len(input)/3)
+	if len(input) < 3 {
+		maxDistance = len(input) - 1
+	}
+	var matches []match
+	for _, candidate := range slices.Compact(slices.Sorted(slices.Values(candidates))) {
+		distance := levenshtein(strings.ToLower(input), strings.ToLower(candidate))
+		isPrefix := input != "" && strings.HasPrefix(candidate, input)
+		if distance <= maxDistance || isPrefix {
+			matches = append(matches, match{candidate, distance})
+		}
+	}
+
+	slices.SortStableFunc(
+		matches, func(a, b match) int {
+			return a.distance - b.distance
+		},
+	)
+
+	suggestions := make([]string, 0, maxSuggestions)
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		suggestions = append(suggestions, matches[i].candidate)
+	}
+	return suggestions
+}
+
+// levenshtein computes the edit distance between two strings: the minimum number of single
+// character insertions, deletions or substitutions needed to change one into the other
+func levenshtein(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type SuggestSuite struct {
+	suite.Suite
+}
+
+func TestSuggestSuite(t *testing.T) {
+	suite.Run(t, new(SuggestSuite))
+}
+
+func (s *SuggestSuite) TestItCanComputeEditDistance() {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"dry-run", "dry-run", 0},
+		{"dryrun", "dry-run", 1},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"naïve", "naive", 1},
+	}
+
+	for _, scenario := range tests {
+		s.Equal(
+			scenario.want,
+			levenshtein(scenario.a, scenario.b),
+			"levenshtein(%q, %q)",
+			scenario.a,
+			scenario.b,
+		)
+	}
+}
+
+func (s *SuggestSuite) TestItSuggestsClosestCandidates() {
+	candidates := []string{"dry-run", "verbose", "version", "force", "format", "v"}
+
+	s.Equal([]string{"dry-run"}, suggest("dryrun", candidates))
+	s.Equal([]string{"force"}, suggest("forse", candidates))
+	s.Equal([]string{"version"}, suggest("versio", candidates))
+	s.Equal([]string{"v", "verbose", "version"}, suggest("ver", candidates))
+	s.Equal([]string{"verbose"}, suggest("verb", candidates))
+	s.Empty(suggest("completely-different", candidates))
+	s.Empty(suggest("x", []string{"a", "b", "c", "d", "e"}))
+	s.Equal([]string{"x"}, suggest("X", []string{"a", "x"}))
+	s.Equal([]string{"db"}, suggest("dv", []string{"db", "api"}))
+	s.Empty(suggest("", []string{"f", "n", "v"}))
+}